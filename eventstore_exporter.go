@@ -17,28 +17,65 @@ func readAndValidateConfig() *config.Config {
 		return nil
 	}
 
-	password := config.EventStorePassword
-	if password != "" {
-		password = "**REDACTED**" // nolint:gosec
+	if config.PrintConfig {
+		if err := config.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
 	}
+
+	redacted := config.Redacted()
 	log.WithFields(log.Fields{
-		"eventStoreURL":             config.EventStoreURL,
-		"eventStoreUser":            config.EventStoreUser,
-		"eventStorePassword":        password,
-		"port":                      config.Port,
-		"timeout":                   config.Timeout,
-		"verbose":                   config.Verbose,
-		"insecureSkipVerify":        config.InsecureSkipVerify,
-		"enableParkedMessagesStats": config.EnableParkedMessagesStats,
-		"streams":                   config.Streams,
+		"eventStoreURL":                  config.EventStoreURL,
+		"connectionString":               redacted.ConnectionString,
+		"eventStoreUser":                 config.EventStoreUser,
+		"eventStorePassword":             redacted.EventStorePassword,
+		"eventStoreUserFile":             config.EventStoreUserFile,
+		"eventStorePasswordFile":         config.EventStorePasswordFile,
+		"eventStoreBearerToken":          redacted.EventStoreBearerToken,
+		"eventStoreBearerTokenFile":      config.EventStoreBearerTokenFile,
+		"oauthTokenURL":                  config.OAuthTokenURL,
+		"oauthClientID":                  config.OAuthClientID,
+		"oauthClientSecret":              redacted.OAuthClientSecret,
+		"oauthScopes":                    config.OAuthScopes,
+		"eventStoreCAFile":               config.EventStoreCAFile,
+		"eventStoreClientCert":           config.EventStoreClientCert,
+		"eventStoreClientKey":            config.EventStoreClientKey,
+		"port":                           config.Port,
+		"timeout":                        config.Timeout,
+		"maxConcurrentReads":             config.MaxConcurrentReads,
+		"readTimeout":                    config.ReadTimeout,
+		"verbose":                        config.Verbose,
+		"insecureSkipVerify":             config.InsecureSkipVerify,
+		"projectionStatsSource":          config.ProjectionStatsSource,
+		"includeOneTimeProjections":      config.IncludeOneTimeProjections,
+		"projectionMetrics":              len(config.ProjectionMetrics),
+		"subscriptionStatsSource":        config.SubscriptionStatsSource,
+		"enableParkedMessagesStats":      config.EnableParkedMessagesStats,
+		"enableParkedMessagesAnalysis":   config.EnableParkedMessagesAnalysis,
+		"parkedMessagesAnalysisMaxCount": config.ParkedMessagesAnalysisMaxCount,
+		"streams":                        config.Streams,
+		"streamMetrics":                  len(config.StreamMetrics),
+		"enableTCPConnectionStats":       config.EnableTCPConnectionStats,
+		"enableScavengeStats":            config.EnableScavengeStats,
+		"enableTLSCertificateStats":      config.EnableTLSCertificateStats,
+		"clockSkewTolerance":             config.ClockSkewTolerance,
+		"metricsVersion":                 config.MetricsVersion,
+		"enableLegacyGaugeMetrics":       config.EnableLegacyGaugeMetrics,
 	}).Infof("EventStore exporter configured")
 
 	return config
 }
 
+func reloadConfig() (*config.Config, error) {
+	return config.Load(os.Args[1:], true)
+}
+
 func setupLogger(config *config.Config) {
 	if config.Verbose {
 		log.SetLevel(log.DebugLevel)
+	} else {
+		log.SetLevel(log.InfoLevel)
 	}
 }
 
@@ -51,5 +88,6 @@ func main() {
 	collector := collector.NewCollector(config, client)
 
 	exporterServer := server.NewExporterServer(config, collector)
+	exporterServer.EnableReload(reloadConfig, server.ConfigUpdaterFunc(setupLogger), client, collector)
 	exporterServer.ListenAndServe()
 }
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// tokens are refreshed this long before they expire, so that they don't expire in the middle of a scrape
+const oauthTokenRefreshMargin = 30 * time.Second
+
+// authenticator provides value of the Authorization header used for EventStore HTTP and gRPC calls
+type authenticator interface {
+	// authorization returns empty string when calls should not be authenticated
+	authorization(ctx context.Context) (string, error)
+}
+
+func newAuthenticator(config *config.Config, credentials *credentialsLoader) authenticator {
+	switch {
+	case config.EventStoreBearerToken != "":
+		return &staticTokenAuthenticator{token: config.EventStoreBearerToken}
+	case config.EventStoreBearerTokenFile != "":
+		return &tokenFileAuthenticator{file: config.EventStoreBearerTokenFile}
+	case config.OAuthTokenURL != "":
+		return newOAuthAuthenticator(config)
+	default:
+		return &basicAuthenticator{credentials: credentials}
+	}
+}
+
+type basicAuthenticator struct {
+	credentials *credentialsLoader
+}
+
+func (auth *basicAuthenticator) authorization(context.Context) (string, error) {
+	credentials, err := auth.credentials.load()
+	if err != nil || credentials.user == "" || credentials.password == "" {
+		return "", err
+	}
+
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials.user+":"+credentials.password)), nil
+}
+
+type staticTokenAuthenticator struct {
+	token string
+}
+
+func (auth *staticTokenAuthenticator) authorization(context.Context) (string, error) {
+	return "Bearer " + auth.token, nil
+}
+
+// tokenFileAuthenticator reads bearer token from file, re-reading it whenever the file changes
+type tokenFileAuthenticator struct {
+	mutex   sync.Mutex
+	file    string
+	modTime time.Time
+	token   string
+}
+
+func (auth *tokenFileAuthenticator) authorization(context.Context) (string, error) {
+	auth.mutex.Lock()
+	defer auth.mutex.Unlock()
+
+	modTimes, err := fileModTimes([]string{auth.file})
+	if err == nil && auth.token != "" && modTimes[0].Equal(auth.modTime) {
+		return "Bearer " + auth.token, nil
+	}
+
+	var token string
+	if err == nil {
+		token, err = readSecretFile(auth.file)
+	}
+	if err == nil && token == "" {
+		err = fmt.Errorf("token file %s is empty", auth.file)
+	}
+
+	if err != nil {
+		if auth.token == "" {
+			return "", fmt.Errorf("error while reading token file: %w", err)
+		}
+
+		log.WithError(err).Warn("Error when re-reading token file, using previously read token")
+		return "Bearer " + auth.token, nil
+	}
+
+	auth.token = token
+	auth.modTime = modTimes[0]
+
+	return "Bearer " + auth.token, nil
+}
+
+// oauthAuthenticator gets access tokens using OAuth2 client credentials flow, caching them until they are about to expire
+type oauthAuthenticator struct {
+	mutex      sync.Mutex
+	config     *config.Config
+	httpClient *http.Client
+	caModTime  time.Time
+	token      string
+	expiry     time.Time
+}
+
+// newOAuthAuthenticator creates authenticator with its own HTTP client, token endpoint is usually not a part
+// of the EventStore cluster, so EventStore TLS settings (CA file, client certificate, skipping verification)
+// don't apply to it. It is verified with system roots, or with OAuth CA file when specified.
+func newOAuthAuthenticator(config *config.Config) *oauthAuthenticator {
+	return &oauthAuthenticator{config: config}
+}
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (auth *oauthAuthenticator) authorization(ctx context.Context) (string, error) {
+	auth.mutex.Lock()
+	defer auth.mutex.Unlock()
+
+	if auth.token != "" && (auth.expiry.IsZero() || time.Now().Before(auth.expiry.Add(-oauthTokenRefreshMargin))) {
+		return "Bearer " + auth.token, nil
+	}
+
+	token, err := auth.requestToken(ctx)
+	if err != nil {
+		if auth.token == "" || (!auth.expiry.IsZero() && !time.Now().Before(auth.expiry)) {
+			return "", fmt.Errorf("error while getting OAuth token: %w", err)
+		}
+
+		// token endpoint may be briefly unavailable, the current token can be used until it actually expires
+		log.WithError(err).WithField("expiry", auth.expiry).Warn("Error when refreshing OAuth token, using previous token")
+		return "Bearer " + auth.token, nil
+	}
+
+	auth.token = token.AccessToken
+	auth.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		auth.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	log.WithField("expiry", auth.expiry).Debug("Got new OAuth token")
+
+	return "Bearer " + auth.token, nil
+}
+
+func (auth *oauthAuthenticator) requestToken(ctx context.Context) (*oauthTokenResponse, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if auth.config.OAuthScopes != "" {
+		form.Set("scope", auth.config.OAuthScopes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, auth.config.OAuthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(url.QueryEscape(auth.config.OAuthClientID), url.QueryEscape(auth.config.OAuthClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient, err := auth.getHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		return nil, fmt.Errorf("token endpoint responded with status code %d", response.StatusCode)
+	}
+
+	token := &oauthTokenResponse{}
+	if err := json.NewDecoder(response.Body).Decode(token); err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken == "" {
+		return nil, errors.New("token endpoint response does not contain access token")
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return nil, fmt.Errorf("unsupported token type %s", token.TokenType)
+	}
+
+	return token, nil
+}
+
+// getHTTPClient returns client used for token requests, rebuilding it whenever OAuth CA file changes
+func (auth *oauthAuthenticator) getHTTPClient() (*http.Client, error) {
+	if auth.config.OAuthCAFile == "" {
+		if auth.httpClient == nil {
+			auth.httpClient = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+		}
+
+		return auth.httpClient, nil
+	}
+
+	modTimes, err := fileModTimes([]string{auth.config.OAuthCAFile})
+	if err == nil && auth.httpClient != nil && modTimes[0].Equal(auth.caModTime) {
+		return auth.httpClient, nil
+	}
+
+	var rootCAs *x509.CertPool
+	if err == nil {
+		rootCAs, err = loadCAFile(auth.config.OAuthCAFile)
+	}
+
+	if err != nil {
+		if auth.httpClient == nil {
+			return nil, err
+		}
+
+		log.WithError(err).Warn("Error when reloading OAuth CA file, using previously loaded one")
+		return auth.httpClient, nil
+	}
+
+	if auth.httpClient != nil {
+		auth.httpClient.CloseIdleConnections()
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+
+	auth.httpClient = &http.Client{Transport: transport}
+	auth.caModTime = modTimes[0]
+
+	return auth.httpClient, nil
+}
+
+func (auth *oauthAuthenticator) close() {
+	auth.mutex.Lock()
+	defer auth.mutex.Unlock()
+
+	if auth.httpClient != nil {
+		auth.httpClient.CloseIdleConnections()
+	}
+}
@@ -6,17 +6,39 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
 	"github.com/marcinbudny/eventstore_exporter/internal/config"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/metadata"
 )
 
 type EventStoreStatsClient struct {
-	httpClient http.Client
-	config     *config.Config
+	currentConfig     atomic.Pointer[config.Config]
+	currentConnection atomic.Pointer[connection]
+	currentReadPool   atomic.Pointer[readPool]
+	readQueueWaits    *readQueueWaits
+}
+
+// connection holds state that depends on connection settings only, so that it survives config reloads
+// that don't change these settings
+type connection struct {
+	config        *config.Config
+	tlsConfig     *tlsConfigLoader
+	credentials   *credentialsLoader
+	authenticator authenticator
+	nodes         *nodeSelector
+
+	parkedMessages   *parkedMessagesCache
+	peerCertificates *peerCertificates
+
+	httpClientMutex     sync.Mutex
+	httpClient          *http.Client
+	httpClientTLSConfig *tls.Config
 }
 
 type Stats struct {
@@ -24,56 +46,186 @@ type Stats struct {
 	Server         *ServerStats
 	ClusterMembers []MemberStats
 	Projections    []ProjectionStats
-	Subscriptions  []SubscriptionStats
-	Streams        []StreamStats
-	TCPConnections []TCPConnectionStats
+	// ProjectionsEnabled is false when projections subsystem is not running
+	ProjectionsEnabled bool
+	ProjectionMetrics  []ProjectionMetricValue
+	Subscriptions      []SubscriptionStats
+	Streams            []StreamStats
+	StreamMetrics      []StreamMetricValue
+	TCPConnections     []TCPConnectionStats
+	Scavenges          []ScavengeStats
+	Certificates       []TLSCertificateStats
+	ReadQueueWait      ReadQueueWaitStats
 }
 
 func New(config *config.Config) *EventStoreStatsClient {
-	esClient := &EventStoreStatsClient{}
-	esClient.config = config
+	esClient := &EventStoreStatsClient{readQueueWaits: newReadQueueWaits()}
+	esClient.currentConfig.Store(config)
+	esClient.currentConnection.Store(newConnection(config))
+	esClient.currentReadPool.Store(newReadPool(config.MaxConcurrentReads, esClient.readQueueWaits))
 
-	if config.InsecureSkipVerify {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint: gosec
-		}
-		esClient.httpClient = http.Client{
-			Transport: tr,
-		}
-	} else {
-		esClient.httpClient = http.Client{}
+	return esClient
+}
+
+func newConnection(config *config.Config) *connection {
+	conn := &connection{
+		config:      config,
+		tlsConfig:   newTLSConfigLoader(config),
+		credentials: newCredentialsLoader(config),
+		nodes:       newNodeSelector(config),
+
+		parkedMessages:   newParkedMessagesCache(),
+		peerCertificates: newPeerCertificates(),
 	}
+	conn.authenticator = newAuthenticator(config, conn.credentials)
 
-	return esClient
+	return conn
 }
 
-func (client *EventStoreStatsClient) getGrpcClient() (*esdb.Client, error) {
-	log.Debug("Creating ES grpc client")
+// UpdateConfig swaps config used by the client, HTTP client, credentials and certificates are
+// only rebuilt when connection settings changed
+func (client *EventStoreStatsClient) UpdateConfig(newConfig *config.Config) {
+	oldConnection := client.connection()
+	if !oldConnection.config.ConnectionSettingsEqual(newConfig) {
+		log.Info("EventStore connection settings changed, recreating connection")
+
+		client.currentConnection.Store(newConnection(newConfig))
+		oldConnection.close()
+	}
+
+	// reads in progress finish using slots of the old pool
+	if client.readPool().size() != newConfig.MaxConcurrentReads {
+		client.currentReadPool.Store(newReadPool(newConfig.MaxConcurrentReads, client.readQueueWaits))
+	}
+
+	client.currentConfig.Store(newConfig)
+}
+
+func (client *EventStoreStatsClient) config() *config.Config {
+	return client.currentConfig.Load()
+}
+
+func (client *EventStoreStatsClient) connection() *connection {
+	return client.currentConnection.Load()
+}
+
+func (client *EventStoreStatsClient) readPool() *readPool {
+	return client.currentReadPool.Load()
+}
+
+// runRead executes read in the shared read pool, with the configured per-read timeout
+func (client *EventStoreStatsClient) runRead(ctx context.Context, read func(ctx context.Context) error) error {
+	return client.readPool().run(ctx, client.config().ReadTimeout, read)
+}
 
-	esURL, err := url.Parse(client.config.EventStoreURL)
+func (conn *connection) close() {
+	conn.httpClientMutex.Lock()
+	defer conn.httpClientMutex.Unlock()
+
+	if conn.httpClient != nil {
+		conn.httpClient.CloseIdleConnections()
+	}
+
+	if oauth, ok := conn.authenticator.(*oauthAuthenticator); ok {
+		oauth.close()
+	}
+}
 
+func (conn *connection) getHTTPClient() (*http.Client, error) {
+	tlsConfig, err := conn.tlsConfig.load()
 	if err != nil {
 		return nil, err
 	}
 
-	esConfig := &esdb.Configuration{
-		Address:                     esURL.Host,
-		DisableTLS:                  esURL.Scheme != "https",
-		SkipCertificateVerification: client.config.InsecureSkipVerify,
-		DiscoveryInterval:           100,
-		GossipTimeout:               5,
-		MaxDiscoverAttempts:         10,
-		KeepAliveInterval:           10 * time.Second,
-		KeepAliveTimeout:            10 * time.Second,
-		Logger:                      loggerAdapter,
+	conn.httpClientMutex.Lock()
+	defer conn.httpClientMutex.Unlock()
+
+	if conn.httpClient != nil && conn.httpClientTLSConfig == tlsConfig {
+		return conn.httpClient, nil
+	}
+
+	if conn.httpClient != nil {
+		conn.httpClient.CloseIdleConnections()
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	conn.httpClient = &http.Client{Transport: transport}
+	conn.httpClientTLSConfig = tlsConfig
+
+	return conn.httpClient, nil
+}
+
+// getGrpcClient returns new grpc client together with context that should be used for calls made with it
+func (client *EventStoreStatsClient) getGrpcClient(ctx context.Context) (*esdb.Client, context.Context, error) {
+	log.Debug("Creating ES grpc client")
+
+	conn := client.connection()
+
+	esConfig, err := conn.getGrpcConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig, err := conn.tlsConfig.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentials, err := conn.credentials.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	esConfig.SkipCertificateVerification = conn.config.InsecureSkipVerify
+	esConfig.RootCAs = tlsConfig.RootCAs
+	esConfig.UserCertFile = conn.config.EventStoreClientCert
+	esConfig.UserKeyFile = conn.config.EventStoreClientKey
+	esConfig.Logger = loggerAdapter
+
+	// basic auth is handled by the esdb client itself, other schemes are passed in call metadata
+	if _, isBasicAuth := conn.authenticator.(*basicAuthenticator); isBasicAuth {
+		if credentials.user != "" && credentials.password != "" {
+			esConfig.Username = credentials.user
+			esConfig.Password = credentials.password
+		}
+	} else {
+		authorization, err := conn.authenticator.authorization(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", authorization)
+	}
+
+	grpcClient, err := esdb.NewClient(esConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return grpcClient, ctx, nil
+}
+
+func (conn *connection) getGrpcConfig() (*esdb.Configuration, error) {
+	if conn.config.ConnectionString != "" {
+		return esdb.ParseConnectionString(conn.config.NormalizedConnectionString())
 	}
 
-	if client.config.EventStoreUser != "" && client.config.EventStorePassword != "" {
-		esConfig.Username = client.config.EventStoreUser
-		esConfig.Password = client.config.EventStorePassword
+	esURL, err := url.Parse(conn.config.EventStoreURL)
+	if err != nil {
+		return nil, err
 	}
 
-	return esdb.NewClient(esConfig)
+	return &esdb.Configuration{
+		Address:             esURL.Host,
+		DisableTLS:          esURL.Scheme != "https",
+		DiscoveryInterval:   100,
+		GossipTimeout:       5,
+		MaxDiscoverAttempts: 10,
+		KeepAliveInterval:   10 * time.Second,
+		KeepAliveTimeout:    10 * time.Second,
+	}, nil
 }
 
 func (client *EventStoreStatsClient) GetStats(ctx context.Context) (*Stats, error) {
@@ -82,8 +234,11 @@ func (client *EventStoreStatsClient) GetStats(ctx context.Context) (*Stats, erro
 
 	stats := &Stats{}
 
+	// subscription and projection stats depend on server version and features
+	getEsInfo := sync.OnceValues(func() (*EsInfo, error) { return client.GetEsInfo(ctx) })
+
 	group.Go(func() error {
-		info, err := client.GetEsInfo(ctx)
+		info, err := getEsInfo()
 		if err != nil {
 			return fmt.Errorf("error while getting ES Info: %w", err)
 		}
@@ -103,17 +258,23 @@ func (client *EventStoreStatsClient) GetStats(ctx context.Context) (*Stats, erro
 	})
 
 	group.Go(func() error {
-		projectionStats, err := client.getProjectionStats(ctx)
+		projectionStats, projectionsEnabled, err := client.getProjectionStats(ctx, getEsInfo)
 		if err != nil {
 			return fmt.Errorf("error while getting projection stats: %w", err)
 		}
 
 		stats.Projections = projectionStats
+		stats.ProjectionsEnabled = projectionsEnabled
+		return nil
+	})
+
+	group.Go(func() error {
+		stats.ProjectionMetrics = client.getProjectionMetrics(ctx)
 		return nil
 	})
 
 	group.Go(func() error {
-		subscriptionStats, err := client.getSubscriptionStats(ctx)
+		subscriptionStats, err := client.getSubscriptionStats(ctx, getEsInfo)
 		if err != nil {
 			return fmt.Errorf("error while getting subscription stats: %w", err)
 		}
@@ -123,12 +284,13 @@ func (client *EventStoreStatsClient) GetStats(ctx context.Context) (*Stats, erro
 	})
 
 	group.Go(func() error {
-		streamStats, err := client.getStreamStats(ctx)
+		streamStats, streamMetrics, err := client.getStreamStats(ctx)
 		if err != nil {
 			return fmt.Errorf("error while getting stream stats: %w", err)
 		}
 
 		stats.Streams = streamStats
+		stats.StreamMetrics = streamMetrics
 		return nil
 	})
 
@@ -139,6 +301,12 @@ func (client *EventStoreStatsClient) GetStats(ctx context.Context) (*Stats, erro
 		}
 
 		stats.ClusterMembers = clusterStats
+		if nodes := client.connection().nodes; nodes != nil {
+			nodes.observe(clusterStats)
+		}
+
+		// reported after cluster stats call, so that certificates of the scraped node are always included
+		stats.Certificates = client.getTLSCertificateStats()
 		return nil
 	})
 
@@ -152,10 +320,22 @@ func (client *EventStoreStatsClient) GetStats(ctx context.Context) (*Stats, erro
 		return nil
 	})
 
+	group.Go(func() error {
+		scavengeStats, err := client.getScavengeStats(ctx)
+		if err != nil {
+			return fmt.Errorf("error while getting scavenge stats: %w", err)
+		}
+
+		stats.Scavenges = scavengeStats
+		return nil
+	})
+
 	if err := group.Wait(); err != nil {
 		return nil, err
 	}
 
+	stats.ReadQueueWait = client.readQueueWaits.stats()
+
 	return stats, nil
 }
 
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_UpdateConfig_KeepsConnectionWhenConnectionSettingsUnchanged(t *testing.T) {
+	client := New(&config.Config{EventStoreURL: "http://localhost:2113"})
+	conn := client.connection()
+
+	newConfig := &config.Config{EventStoreURL: "http://localhost:2113", EnableScavengeStats: true}
+	client.UpdateConfig(newConfig)
+
+	if client.connection() != conn {
+		t.Error("Expected connection to be kept")
+	}
+	if client.config() != newConfig {
+		t.Error("Expected config to be updated")
+	}
+}
+
+func Test_UpdateConfig_RecreatesConnectionWhenConnectionSettingsChanged(t *testing.T) {
+	client := New(&config.Config{EventStoreURL: "http://localhost:2113"})
+	conn := client.connection()
+
+	client.UpdateConfig(&config.Config{EventStoreURL: "http://localhost:2113", EventStoreBearerToken: "token"})
+
+	if client.connection() == conn {
+		t.Error("Expected connection to be recreated")
+	}
+	if _, isStaticToken := client.connection().authenticator.(*staticTokenAuthenticator); !isStaticToken {
+		t.Error("Expected new connection to use new authentication settings")
+	}
+}
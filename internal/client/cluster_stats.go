@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 )
 
 type gossipEnvelope struct {
@@ -16,11 +17,19 @@ const (
 )
 
 type MemberStats struct {
-	HTTPEndpointIP   string `json:"httpEndPointIp"`
-	HTTPEndpointPort int    `json:"httpEndPointPort"`
+	InstanceID       string                `json:"instanceId"`
+	HTTPEndpointIP   string                `json:"httpEndPointIp"`
+	HTTPEndpointPort int                   `json:"httpEndPointPort"`
+	State            string                `json:"state"`
+	EpochNumber      int64                 `json:"epochNumber"`
+	TimeStamp        LenientDotNetDateTime `json:"timeStamp"`
 	IsAlive          bool
 }
 
+func (member MemberStats) Name() string {
+	return fmt.Sprintf("%s:%d", member.HTTPEndpointIP, member.HTTPEndpointPort)
+}
+
 func (client *EventStoreStatsClient) getClusterStats(ctx context.Context) (stats []MemberStats, err error) {
 	gossip, err := esHTTPGetAndParse[gossipEnvelope](ctx, client, "/gossip", false)
 	if err != nil {
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+type credentials struct {
+	user     string
+	password string
+}
+
+// credentialsLoader provides EventStore credentials from config, reading user and password
+// from the configured files and re-reading them whenever any of the files changes
+type credentialsLoader struct {
+	mutex       sync.Mutex
+	config      *config.Config
+	modTimes    []time.Time
+	credentials *credentials
+}
+
+func newCredentialsLoader(config *config.Config) *credentialsLoader {
+	return &credentialsLoader{config: config}
+}
+
+func (loader *credentialsLoader) files() []string {
+	files := []string{}
+	for _, file := range []string{loader.config.EventStoreUserFile, loader.config.EventStorePasswordFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+func (loader *credentialsLoader) load() (credentials, error) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+
+	modTimes, err := fileModTimes(loader.files())
+	if err == nil && loader.credentials != nil && modTimesEqual(modTimes, loader.modTimes) {
+		return *loader.credentials, nil
+	}
+
+	var loaded credentials
+	if err == nil {
+		loaded, err = loader.read()
+	}
+
+	if err != nil {
+		if loader.credentials == nil {
+			return credentials{}, err
+		}
+
+		log.WithError(err).Warn("Error when re-reading EventStore credentials, using previously read ones")
+		return *loader.credentials, nil
+	}
+
+	if loader.credentials != nil && loaded != *loader.credentials {
+		log.Info("EventStore credentials changed, reloaded")
+	}
+
+	loader.credentials = &loaded
+	loader.modTimes = modTimes
+
+	return loaded, nil
+}
+
+func (loader *credentialsLoader) read() (credentials, error) {
+	loaded := credentials{
+		user:     loader.config.EventStoreUser,
+		password: loader.config.EventStorePassword,
+	}
+
+	if loader.config.EventStoreUserFile != "" {
+		user, err := readSecretFile(loader.config.EventStoreUserFile)
+		if err != nil {
+			return credentials{}, fmt.Errorf("error while reading user file: %w", err)
+		}
+
+		loaded.user = user
+	}
+
+	if loader.config.EventStorePasswordFile != "" {
+		password, err := readSecretFile(loader.config.EventStorePasswordFile)
+		if err != nil {
+			return credentials{}, fmt.Errorf("error while reading password file: %w", err)
+		}
+
+		loaded.password = password
+	}
+
+	if (loaded.user == "") != (loaded.password == "") {
+		return credentials{}, errors.New("EventStore user and password should both be non-empty")
+	}
+
+	return loaded, nil
+}
+
+// secret files usually end with a new line, which is not part of the secret
+func readSecretFile(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
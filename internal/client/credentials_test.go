@@ -0,0 +1,82 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_CredentialsFromConfig(t *testing.T) {
+	loader := newCredentialsLoader(&config.Config{EventStoreUser: "user", EventStorePassword: "password"})
+
+	loaded, err := loader.load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if loaded != (credentials{user: "user", password: "password"}) {
+		t.Errorf("Unexpected credentials %+v", loaded)
+	}
+}
+
+func Test_CredentialsReReadWhenFilesChange(t *testing.T) {
+	dir := t.TempDir()
+	userFile := filepath.Join(dir, "user")
+	passwordFile := filepath.Join(dir, "password")
+	writeSecretFile(t, userFile, "monitoring\n", time.Now())
+	writeSecretFile(t, passwordFile, "first\n", time.Now())
+
+	loader := newCredentialsLoader(&config.Config{EventStoreUserFile: userFile, EventStorePasswordFile: passwordFile})
+
+	loaded, err := loader.load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded != (credentials{user: "monitoring", password: "first"}) {
+		t.Errorf("Unexpected credentials %+v", loaded)
+	}
+
+	writeSecretFile(t, passwordFile, "second", time.Now().Add(time.Minute))
+
+	loaded, err = loader.load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded != (credentials{user: "monitoring", password: "second"}) {
+		t.Errorf("Expected rotated password, got %+v", loaded)
+	}
+
+	if err := os.Remove(passwordFile); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err = loader.load()
+	if err != nil || loaded.password != "second" {
+		t.Errorf("Expected previous credentials to be kept when file is missing, got %+v, error %v", loaded, err)
+	}
+}
+
+func Test_CredentialsErrorOnMissingFile(t *testing.T) {
+	loader := newCredentialsLoader(&config.Config{
+		EventStoreUser:         "user",
+		EventStorePasswordFile: filepath.Join(t.TempDir(), "missing"),
+	})
+
+	if _, err := loader.load(); err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func writeSecretFile(t *testing.T, file string, content string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DotNetDateTime is a timestamp serialized by EventStore, which may or may not carry a time zone designator
+type DotNetDateTime struct {
+	time.Time
+}
+
+// LenientDotNetDateTime is DotNetDateTime that is left zero when it can't be parsed, for optional values
+// that shouldn't fail parsing of the whole response
+type LenientDotNetDateTime struct {
+	DotNetDateTime
+}
+
+// DotNetTimeSpan is a duration serialized by EventStore in .NET TimeSpan format, e.g. "1.02:03:04.5000000"
+type DotNetTimeSpan struct {
+	time.Duration
+}
+
+var dotNetDateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.9999999",
+}
+
+func (dateTime *DotNetDateTime) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if value == nil || *value == "" {
+		dateTime.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := parseDotNetDateTime(*value)
+	if err != nil {
+		return err
+	}
+
+	dateTime.Time = parsed
+	return nil
+}
+
+func (dateTime *LenientDotNetDateTime) UnmarshalJSON(data []byte) error {
+	if err := dateTime.DotNetDateTime.UnmarshalJSON(data); err != nil {
+		log.WithError(err).Debug("Ignoring invalid date time")
+		dateTime.Time = time.Time{}
+	}
+
+	return nil
+}
+
+func parseDotNetDateTime(value string) (time.Time, error) {
+	for _, layout := range dotNetDateTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date time: %s", value)
+}
+
+func (timeSpan *DotNetTimeSpan) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if value == nil || *value == "" {
+		timeSpan.Duration = 0
+		return nil
+	}
+
+	parsed, err := parseDotNetTimeSpan(*value)
+	if err != nil {
+		return err
+	}
+
+	timeSpan.Duration = parsed
+	return nil
+}
+
+// parseDotNetTimeSpan supports both constant ("[-][d.]hh:mm:ss[.fffffff]") and general ("[-]d:hh:mm:ss[.fffffff]") formats
+func parseDotNetTimeSpan(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid time span: %s", value)
+
+	negative := strings.HasPrefix(value, "-")
+	parts := strings.Split(strings.TrimPrefix(value, "-"), ":")
+
+	days := "0"
+	switch len(parts) {
+	case 3:
+		if dayPart, hourPart, found := strings.Cut(parts[0], "."); found {
+			days = dayPart
+			parts[0] = hourPart
+		}
+	case 4:
+		days = parts[0]
+		parts = parts[1:]
+	default:
+		return 0, invalid
+	}
+
+	numDays, err := strconv.ParseInt(days, 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, invalid
+	}
+
+	duration := time.Duration(numDays)*24*time.Hour +
+		time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+
+	if negative {
+		return -duration, nil
+	}
+	return duration, nil
+}
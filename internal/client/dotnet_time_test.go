@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func Test_Parse_DotNetDateTime(t *testing.T) {
+	tests := []struct {
+		json string
+		want time.Time
+	}{
+		{json: `"2024-03-12T10:11:12.1234567Z"`, want: time.Date(2024, 3, 12, 10, 11, 12, 123456700, time.UTC)},
+		{json: `"2024-03-12T12:11:12.1234567+02:00"`, want: time.Date(2024, 3, 12, 10, 11, 12, 123456700, time.UTC)},
+		{json: `"2024-03-12T10:11:12.1234567"`, want: time.Date(2024, 3, 12, 10, 11, 12, 123456700, time.UTC)},
+		{json: `"2024-03-12T10:11:12"`, want: time.Date(2024, 3, 12, 10, 11, 12, 0, time.UTC)},
+		{json: `null`, want: time.Time{}},
+	}
+
+	for _, test := range tests {
+		var dateTime DotNetDateTime
+		if err := json.Unmarshal([]byte(test.json), &dateTime); err != nil {
+			t.Errorf("Unexpected error when parsing %s: %v", test.json, err)
+		} else if !dateTime.Equal(test.want) {
+			t.Errorf("Expected %s to be parsed as %v, got %v", test.json, test.want, dateTime.Time)
+		}
+	}
+}
+
+func Test_Parse_Invalid_DotNetDateTime(t *testing.T) {
+	var dateTime DotNetDateTime
+	if err := json.Unmarshal([]byte(`"yesterday"`), &dateTime); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func Test_Parse_Invalid_LenientDotNetDateTime(t *testing.T) {
+	var dateTime LenientDotNetDateTime
+	if err := json.Unmarshal([]byte(`"yesterday"`), &dateTime); err != nil || !dateTime.IsZero() {
+		t.Errorf("Expected zero time without error, got %v, %v", dateTime.Time, err)
+	}
+}
+
+func Test_Parse_DotNetTimeSpan(t *testing.T) {
+	tests := []struct {
+		json string
+		want time.Duration
+	}{
+		{json: `"00:00:01.5000000"`, want: 1500 * time.Millisecond},
+		{json: `"01:02:03"`, want: time.Hour + 2*time.Minute + 3*time.Second},
+		{json: `"2.01:00:00.2500000"`, want: 49*time.Hour + 250*time.Millisecond},
+		{json: `"0:00:00:00.0010000"`, want: time.Millisecond},
+		{json: `"1:00:00:30.0000000"`, want: 24*time.Hour + 30*time.Second},
+		{json: `"-00:00:02"`, want: -2 * time.Second},
+		{json: `null`, want: 0},
+	}
+
+	for _, test := range tests {
+		var timeSpan DotNetTimeSpan
+		if err := json.Unmarshal([]byte(test.json), &timeSpan); err != nil {
+			t.Errorf("Unexpected error when parsing %s: %v", test.json, err)
+		} else if timeSpan.Duration != test.want {
+			t.Errorf("Expected %s to be parsed as %v, got %v", test.json, test.want, timeSpan.Duration)
+		}
+	}
+}
+
+func Test_Parse_Invalid_DotNetTimeSpan(t *testing.T) {
+	for _, value := range []string{`"12"`, `"aa:bb:cc"`, `"1:2:3:4:5"`} {
+		var timeSpan DotNetTimeSpan
+		if err := json.Unmarshal([]byte(value), &timeSpan); err == nil {
+			t.Errorf("Expected error when parsing %s", value)
+		}
+	}
+}
@@ -43,3 +43,22 @@ func Test_EsVersion_IsVersionLowerThan(t *testing.T) {
 		}
 	}
 }
+
+func Test_UsesParkedMessagesCountFromSubscriptionInfo(t *testing.T) {
+	tests := []struct {
+		version EventStoreVersion
+		want    bool
+	}{
+		{version: "21.10.9.0", want: false},
+		{version: "22.10.0.0", want: true},
+		{version: "24.10.1.1930", want: true},
+		{version: "25.0.0.0", want: true},
+		{version: "", want: false},
+	}
+
+	for _, test := range tests {
+		if got := usesParkedMessagesCountFromSubscriptionInfo(test.version); got != test.want {
+			t.Errorf("Expected parked messages count from subscription info for version %q to be %v", test.version, test.want)
+		}
+	}
+}
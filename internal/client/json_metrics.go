@@ -0,0 +1,62 @@
+package client
+
+import (
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	"github.com/marcinbudny/eventstore_exporter/internal/jsonpath"
+	log "github.com/sirupsen/logrus"
+)
+
+type jsonMetricValue struct {
+	labelValues []string
+	value       float64
+}
+
+// jsonMetricValues selects values of user-defined metric from a decoded JSON document, label values
+// are in the order of metric label names
+func jsonMetricValues(metric config.JSONMetric, document any, logger *log.Entry) []jsonMetricValue {
+	// paths are validated when config is loaded
+	path, err := jsonpath.Compile(metric.Path)
+	if err != nil {
+		return nil
+	}
+
+	labelNames := metric.LabelNames()
+	fieldValues := make([]string, 0, len(labelNames)-len(metric.Labels))
+	for _, label := range labelNames[len(metric.Labels):] {
+		fieldValues = append(fieldValues, labelFieldValue(metric.LabelFields[label], document))
+	}
+
+	var values []jsonMetricValue
+	for _, match := range path.Find(document) {
+		value, ok := jsonpath.Number(match.Value)
+		if !ok {
+			logger.WithFields(log.Fields{"metric": metric.Name, "path": metric.Path}).Debug("Skipping metric value that is not a number")
+			continue
+		}
+
+		var labelValues []string
+		if len(labelNames) > 0 {
+			labelValues = append(match.Wildcards, fieldValues...)
+		}
+
+		values = append(values, jsonMetricValue{labelValues: labelValues, value: value})
+	}
+
+	return values
+}
+
+// labelFieldValue returns string selected by path, or empty string when there is none
+func labelFieldValue(fieldPath string, document any) string {
+	path, err := jsonpath.Compile(fieldPath)
+	if err != nil {
+		return ""
+	}
+
+	for _, match := range path.Find(document) {
+		if value, ok := match.Value.(string); ok {
+			return value
+		}
+	}
+
+	return ""
+}
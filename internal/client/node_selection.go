@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// nodeSelector chooses the node scraped over HTTP when connection string lists multiple nodes
+// or uses discovery, following node preference of the connection string
+type nodeSelector struct {
+	mutex      sync.Mutex
+	seeds      []string
+	scheme     string
+	preference esdb.NodePreference
+	selected   string
+}
+
+func newNodeSelector(config *config.Config) *nodeSelector {
+	if config.ConnectionString == "" {
+		return nil
+	}
+
+	esConfig, err := esdb.ParseConnectionString(config.NormalizedConnectionString())
+	if err != nil || (!esConfig.DnsDiscover && len(esConfig.GossipSeeds) == 0) {
+		return nil
+	}
+
+	selector := &nodeSelector{scheme: "https", preference: esConfig.NodePreference}
+	if esConfig.DisableTLS {
+		selector.scheme = "http"
+	}
+
+	if len(esConfig.GossipSeeds) > 0 {
+		for _, seed := range esConfig.GossipSeeds {
+			selector.seeds = append(selector.seeds, selector.scheme+"://"+seed.String())
+		}
+	} else {
+		selector.seeds = []string{selector.scheme + "://" + esConfig.Address}
+	}
+
+	return selector
+}
+
+func (client *EventStoreStatsClient) getBaseURL(ctx context.Context) (string, error) {
+	conn := client.connection()
+	if conn.nodes == nil {
+		return conn.config.EventStoreURL, nil
+	}
+
+	return conn.nodes.get(ctx, func(ctx context.Context, seed string) ([]MemberStats, error) {
+		jsonBytes, err := conn.httpGet(ctx, seed+"/gossip", false)
+		if err != nil {
+			return nil, err
+		}
+
+		gossip := gossipEnvelope{}
+		if err := json.Unmarshal(jsonBytes, &gossip); err != nil {
+			return nil, err
+		}
+
+		return gossip.Members, nil
+	})
+}
+
+func (selector *nodeSelector) get(ctx context.Context, getGossip func(context.Context, string) ([]MemberStats, error)) (string, error) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	if selector.selected != "" {
+		return selector.selected, nil
+	}
+
+	var errs []error
+	for _, seed := range selector.seeds {
+		members, err := getGossip(ctx, seed)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if member, found := selectNode(members, selector.preference); found {
+			selector.selected = selector.scheme + "://" + member.Name()
+			log.WithField("node", selector.selected).Info("Selected EventStore node to scrape")
+
+			return selector.selected, nil
+		}
+	}
+
+	return "", errors.Join(append([]error{errors.New("could not select EventStore node to scrape")}, errs...)...)
+}
+
+// observe updates selected node based on the latest gossip, e.g. after leader election
+func (selector *nodeSelector) observe(members []MemberStats) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	if selector.preference == esdb.NodePreferenceRandom && selector.selected != "" {
+		return
+	}
+
+	if member, found := selectNode(members, selector.preference); found {
+		selected := selector.scheme + "://" + member.Name()
+		if selected != selector.selected {
+			log.WithField("node", selected).Info("Selected EventStore node to scrape")
+			selector.selected = selected
+		}
+	}
+}
+
+// reset makes the next call select node again, e.g. after the selected node became unreachable
+func (selector *nodeSelector) reset() {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	selector.selected = ""
+}
+
+func selectNode(members []MemberStats, preference esdb.NodePreference) (MemberStats, bool) {
+	alive := make([]MemberStats, 0, len(members))
+	for _, member := range members {
+		if member.IsAlive {
+			alive = append(alive, member)
+		}
+	}
+
+	if len(alive) == 0 {
+		return MemberStats{}, false
+	}
+
+	if preference == esdb.NodePreferenceRandom {
+		return alive[rand.IntN(len(alive))], true // nolint: gosec
+	}
+
+	// when there is no member in the preferred state, fall back to the leader, then to any alive member
+	for _, state := range []esdb.NodePreference{preference, esdb.NodePreferenceLeader} {
+		for _, member := range alive {
+			if strings.EqualFold(member.State, string(state)) {
+				return member, true
+			}
+		}
+	}
+
+	return alive[0], true
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+var testMembers = []MemberStats{
+	{HTTPEndpointIP: "10.0.0.1", HTTPEndpointPort: 2113, State: "Follower", IsAlive: true},
+	{HTTPEndpointIP: "10.0.0.2", HTTPEndpointPort: 2113, State: "Leader", IsAlive: true},
+	{HTTPEndpointIP: "10.0.0.3", HTTPEndpointPort: 2113, State: "ReadOnlyReplica", IsAlive: false},
+}
+
+func Test_SelectNode(t *testing.T) {
+	tests := []struct {
+		preference esdb.NodePreference
+		expected   string
+	}{
+		{esdb.NodePreferenceLeader, "10.0.0.2:2113"},
+		{esdb.NodePreferenceFollower, "10.0.0.1:2113"},
+		{esdb.NodePreferenceReadOnlyReplica, "10.0.0.2:2113"}, // replica is dead, falls back to leader
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.preference), func(t *testing.T) {
+			member, found := selectNode(testMembers, test.preference)
+			if !found || member.Name() != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, member.Name())
+			}
+		})
+	}
+}
+
+func Test_SelectNode_NoAliveMembers(t *testing.T) {
+	if _, found := selectNode([]MemberStats{{State: "Leader"}}, esdb.NodePreferenceLeader); found {
+		t.Error("Expected no member to be selected")
+	}
+}
+
+func Test_NodeSelector(t *testing.T) {
+	selector := newNodeSelector(&config.Config{ConnectionString: "kurrentdb://node1:2113,node2:2113?nodePreference=follower"})
+	if selector == nil {
+		t.Fatal("Expected node selector for connection string with multiple nodes")
+	}
+
+	var requestedSeeds []string
+	getGossip := func(_ context.Context, seed string) ([]MemberStats, error) {
+		requestedSeeds = append(requestedSeeds, seed)
+		if seed == "https://node1:2113" {
+			return nil, errors.New("connection refused")
+		}
+
+		return testMembers, nil
+	}
+
+	selected, err := selector.get(context.Background(), getGossip)
+	if err != nil || selected != "https://10.0.0.1:2113" {
+		t.Errorf("Unexpected node %s, error %v", selected, err)
+	}
+
+	if len(requestedSeeds) != 2 {
+		t.Errorf("Expected gossip to be requested from 2 seeds, got %v", requestedSeeds)
+	}
+
+	selector.observe([]MemberStats{
+		{HTTPEndpointIP: "10.0.0.1", HTTPEndpointPort: 2113, State: "Leader", IsAlive: true},
+		{HTTPEndpointIP: "10.0.0.2", HTTPEndpointPort: 2113, State: "Follower", IsAlive: true},
+	})
+
+	selected, _ = selector.get(context.Background(), getGossip)
+	if selected != "https://10.0.0.2:2113" {
+		t.Errorf("Expected selected node to follow gossip, got %s", selected)
+	}
+}
+
+func Test_NoNodeSelectorForSingleNode(t *testing.T) {
+	if newNodeSelector(&config.Config{ConnectionString: "esdb://localhost:2113?tls=false"}) != nil {
+		t.Error("Expected no node selector for single node connection string")
+	}
+
+	if newNodeSelector(&config.Config{EventStoreURL: "http://localhost:2113"}) != nil {
+		t.Error("Expected no node selector without connection string")
+	}
+}
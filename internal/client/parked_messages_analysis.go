@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+)
+
+const (
+	parkedMessagesPageSize = 100
+	maxParkReasonLength    = 100
+	unknownParkedEventType = "unknown"
+)
+
+var (
+	// quoted values and tokens containing digits, like IDs, numbers or timestamps, make reasons of the same failure differ
+	parkReasonQuotedValue    = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+	parkReasonTokenWithDigit = regexp.MustCompile(`[\w.@:/+-]*\d[\w.@:/+-]*`)
+)
+
+// ParkedMessageAgeBuckets are upper bounds of parked message age histogram buckets, in seconds
+var ParkedMessageAgeBuckets = []float64{60, 300, 900, 3600, 6 * 3600, 24 * 3600, 7 * 24 * 3600}
+
+// ParkedMessagesAnalysis describes parked messages of a subscription, limited to the oldest messages up to
+// the configured max count
+type ParkedMessagesAnalysis struct {
+	AnalyzedCount uint64
+	AgeSum        float64
+	AgeBuckets    map[float64]uint64
+	EventTypes    map[string]int64
+	ParkReasons   map[string]int64
+}
+
+type parkedMessageMetadata struct {
+	Reason string `json:"reason"`
+}
+
+func (client *EventStoreStatsClient) getParkedMessagesAnalysis(ctx context.Context, grpcClient *esdb.Client, eventStreamID, groupName string) (*ParkedMessagesAnalysis, error) {
+	maxCount := uint64(client.config().ParkedMessagesAnalysisMaxCount)
+	analysis := newParkedMessagesAnalysis()
+	now := time.Now()
+
+	// messages before truncate before position (replayed already) are not returned by reads
+	var position esdb.StreamPosition = esdb.Start{}
+	for analyzed := uint64(0); analyzed < maxCount; {
+		events, err := readEvents(ctx, grpcClient, parkedStreamID(eventStreamID, groupName),
+			esdb.ReadStreamOptions{Direction: esdb.Forwards, From: position, ResolveLinkTos: true},
+			min(parkedMessagesPageSize, maxCount-analyzed))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			analysis.add(event, now)
+		}
+
+		if len(events) < parkedMessagesPageSize {
+			break
+		}
+
+		analyzed += uint64(len(events))
+		position = esdb.Revision(events[len(events)-1].OriginalEvent().EventNumber + 1)
+	}
+
+	return analysis, nil
+}
+
+func newParkedMessagesAnalysis() *ParkedMessagesAnalysis {
+	analysis := &ParkedMessagesAnalysis{
+		AgeBuckets:  make(map[float64]uint64, len(ParkedMessageAgeBuckets)),
+		EventTypes:  make(map[string]int64),
+		ParkReasons: make(map[string]int64),
+	}
+	for _, upperBound := range ParkedMessageAgeBuckets {
+		analysis.AgeBuckets[upperBound] = 0
+	}
+
+	return analysis
+}
+
+// add records a message read from parked messages stream, parked messages are links to original events,
+// link is created when the message is parked
+func (analysis *ParkedMessagesAnalysis) add(event *esdb.ResolvedEvent, now time.Time) {
+	parked := event.OriginalEvent()
+
+	age := now.Sub(parked.CreatedDate).Seconds()
+	analysis.AnalyzedCount++
+	analysis.AgeSum += age
+	for _, upperBound := range ParkedMessageAgeBuckets {
+		if age <= upperBound {
+			analysis.AgeBuckets[upperBound]++
+		}
+	}
+
+	eventType := unknownParkedEventType
+	if event.Event != nil {
+		eventType = event.Event.EventType
+	}
+	analysis.EventTypes[eventType]++
+
+	if reason := parkReason(parked.UserMetadata); reason != "" {
+		analysis.ParkReasons[reason]++
+	}
+}
+
+func parkReason(metadata []byte) string {
+	var parsed parkedMessageMetadata
+	if len(metadata) == 0 || json.Unmarshal(metadata, &parsed) != nil {
+		return ""
+	}
+
+	return normalizeParkReason(parsed.Reason)
+}
+
+// normalizeParkReason keeps reasons bounded as label values: reasons are often exception messages that contain
+// IDs of the failed messages, so only the first line is used, variable parts are replaced with placeholders
+// and the result is truncated
+func normalizeParkReason(reason string) string {
+	reason, _, _ = strings.Cut(reason, "\n")
+	reason = parkReasonQuotedValue.ReplaceAllString(reason, "<value>")
+	reason = parkReasonTokenWithDigit.ReplaceAllString(reason, "<id>")
+	reason = strings.Join(strings.Fields(reason), " ")
+
+	runes := []rune(reason)
+	if len(runes) > maxParkReasonLength {
+		runes = runes[:maxParkReasonLength]
+	}
+
+	return string(runes)
+}
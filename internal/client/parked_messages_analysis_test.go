@@ -0,0 +1,71 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+)
+
+func Test_ParkedMessagesAnalysis(t *testing.T) {
+	now := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+
+	parked := func(eventType string, age time.Duration, metadata string) *esdb.ResolvedEvent {
+		event := &esdb.ResolvedEvent{
+			Link: &esdb.RecordedEvent{EventType: "$>", CreatedDate: now.Add(-age), UserMetadata: []byte(metadata)},
+		}
+		if eventType != "" {
+			event.Event = &esdb.RecordedEvent{EventType: eventType, CreatedDate: now.Add(-age - time.Hour)}
+		}
+		return event
+	}
+
+	analysis := newParkedMessagesAnalysis()
+	for _, event := range []*esdb.ResolvedEvent{
+		parked("OrderPlaced", 30*time.Second, `{"reason": "Timeout"}`),
+		parked("OrderPlaced", 10*time.Minute, `{"Reason": "Timeout"}`),
+		parked("OrderShipped", 2*time.Hour, `not json`),
+		parked("", 2*time.Hour, ``),
+	} {
+		analysis.add(event, now)
+	}
+
+	if analysis.AnalyzedCount != 4 {
+		t.Errorf("Expected 4 analyzed messages, got %d", analysis.AnalyzedCount)
+	}
+	if analysis.AgeBuckets[60] != 1 || analysis.AgeBuckets[900] != 2 || analysis.AgeBuckets[6*3600] != 4 {
+		t.Errorf("Unexpected age buckets %v", analysis.AgeBuckets)
+	}
+	if analysis.EventTypes["OrderPlaced"] != 2 || analysis.EventTypes["OrderShipped"] != 1 || analysis.EventTypes[unknownParkedEventType] != 1 {
+		t.Errorf("Unexpected event types %v", analysis.EventTypes)
+	}
+	if len(analysis.ParkReasons) != 1 || analysis.ParkReasons["Timeout"] != 2 {
+		t.Errorf("Unexpected park reasons %v", analysis.ParkReasons)
+	}
+}
+
+func Test_ParkReasonIsTruncated(t *testing.T) {
+	reason := parkReason([]byte(`{"reason": "` + strings.Repeat("x", 200) + `"}`))
+
+	if len(reason) != maxParkReasonLength {
+		t.Errorf("Expected reason to be truncated to %d characters, got %d", maxParkReasonLength, len(reason))
+	}
+}
+
+func Test_ParkReasonIsNormalized(t *testing.T) {
+	for _, test := range []struct {
+		reason   string
+		expected string
+	}{
+		{"Timeout", "Timeout"},
+		{"Order 12345 not found", "Order <id> not found"},
+		{"Customer 'john@example.com' is blocked", "Customer <value> is blocked"},
+		{"Failed to process 3f2504e0-4f89-11d3-9a0c-0305e82c3301 at 2024-03-12T10:00:00Z", "Failed to process <id> at <id>"},
+		{"Handler failed\n   at Handler.Handle() in Handler.cs:line 42", "Handler failed"},
+	} {
+		if reason := normalizeParkReason(test.reason); reason != test.expected {
+			t.Errorf("Expected %q to be normalized to %q, got %q", test.reason, test.expected, reason)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// parkedMessagesCache remembers per parked messages stream the position range seen by the previous scrape,
+// together with creation date of the oldest parked message, so that the oldest message is only read again
+// when the range changed
+type parkedMessagesCache struct {
+	mutex   sync.Mutex
+	entries map[string]parkedMessagesCacheEntry
+}
+
+type parkedMessagesCacheEntry struct {
+	lastEventNumber uint64
+	truncateBefore  uint64
+	oldestCreated   time.Time // zero when oldest message was not found
+}
+
+func newParkedMessagesCache() *parkedMessagesCache {
+	return &parkedMessagesCache{entries: make(map[string]parkedMessagesCacheEntry)}
+}
+
+func (cache *parkedMessagesCache) get(parkedStreamID string, lastEventNumber uint64, truncateBefore uint64) (oldestCreated time.Time, ok bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, found := cache.entries[parkedStreamID]
+	if !found || entry.lastEventNumber != lastEventNumber || entry.truncateBefore != truncateBefore {
+		return time.Time{}, false
+	}
+
+	return entry.oldestCreated, true
+}
+
+func (cache *parkedMessagesCache) set(parkedStreamID string, entry parkedMessagesCacheEntry) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries[parkedStreamID] = entry
+}
+
+func (cache *parkedMessagesCache) remove(parkedStreamID string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.entries, parkedStreamID)
+}
+
+// retain evicts entries of subscriptions that no longer exist
+func (cache *parkedMessagesCache) retain(parkedStreamIDs map[string]bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for parkedStreamID := range cache.entries {
+		if !parkedStreamIDs[parkedStreamID] {
+			delete(cache.entries, parkedStreamID)
+		}
+	}
+}
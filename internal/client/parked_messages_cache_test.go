@@ -0,0 +1,42 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ParkedMessagesCache_HitOnlyWhenRangeUnchanged(t *testing.T) {
+	cache := newParkedMessagesCache()
+	created := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+
+	if _, ok := cache.get("stream", 10, 5); ok {
+		t.Error("Expected miss on empty cache")
+	}
+
+	cache.set("stream", parkedMessagesCacheEntry{lastEventNumber: 10, truncateBefore: 5, oldestCreated: created})
+
+	if oldest, ok := cache.get("stream", 10, 5); !ok || !oldest.Equal(created) {
+		t.Errorf("Expected hit with cached created date, got %v, %t", oldest, ok)
+	}
+	if _, ok := cache.get("stream", 11, 5); ok {
+		t.Error("Expected miss when new message was parked")
+	}
+	if _, ok := cache.get("stream", 10, 6); ok {
+		t.Error("Expected miss when parked messages were replayed")
+	}
+}
+
+func Test_ParkedMessagesCache_RetainEvictsRemovedSubscriptions(t *testing.T) {
+	cache := newParkedMessagesCache()
+	cache.set("kept", parkedMessagesCacheEntry{lastEventNumber: 1})
+	cache.set("removed", parkedMessagesCacheEntry{lastEventNumber: 1})
+
+	cache.retain(map[string]bool{"kept": true})
+
+	if _, ok := cache.get("kept", 1, 0); !ok {
+		t.Error("Expected entry of existing subscription to be kept")
+	}
+	if _, ok := cache.get("removed", 1, 0); ok {
+		t.Error("Expected entry of removed subscription to be evicted")
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+)
+
+const (
+	// ParkedMessagesSourceSubscriptionInfo means parked messages count was reported by the server in persistent subscription info
+	ParkedMessagesSourceSubscriptionInfo = "subscription_info"
+	// ParkedMessagesSourceStream means parked messages count was calculated from parked messages stream
+	ParkedMessagesSourceStream = "stream"
+
+	// first version reporting parked messages count in persistent subscription info
+	parkedMessagesCountMinVersion = "22.10"
+)
+
+type subscriptionKey struct {
+	eventStreamID string
+	groupName     string
+}
+
+func usesParkedMessagesCountFromSubscriptionInfo(esVersion EventStoreVersion) bool {
+	return esVersion.IsAtLeastVersion(parkedMessagesCountMinVersion)
+}
+
+// getParkedMessagesCounts lists all persistent subscriptions and returns parked messages counts they report
+func (client *EventStoreStatsClient) getParkedMessagesCounts(ctx context.Context, grpcClient *esdb.Client) (map[subscriptionKey]int64, error) {
+	var subscriptions []esdb.PersistentSubscriptionInfo
+	err := client.runRead(ctx, func(ctx context.Context) (err error) {
+		subscriptions, err = grpcClient.ListAllPersistentSubscriptions(ctx, esdb.ListPersistentSubscriptionsOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[subscriptionKey]int64, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.Stats != nil {
+			counts[subscriptionKey{subscription.EventSource, subscription.GroupName}] = subscription.Stats.ParkedMessagesCount
+		}
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// ProjectionMetricValue is a value of user-defined metric read from state or result of a projection,
+// label values are in the order of metric label names
+type ProjectionMetricValue struct {
+	Metric      config.JSONMetric
+	Projection  string
+	Partition   string
+	LabelValues []string
+	Value       float64
+}
+
+// getProjectionMetrics reads configured projection documents, documents that can't be read are skipped
+// so that a single missing projection doesn't fail the whole scrape
+func (client *EventStoreStatsClient) getProjectionMetrics(ctx context.Context) []ProjectionMetricValue {
+	projectionMetrics := client.config().ProjectionMetrics
+	if len(projectionMetrics) == 0 {
+		return nil
+	}
+
+	values := make([][]ProjectionMetricValue, len(projectionMetrics))
+	var wg sync.WaitGroup
+
+	for i, projection := range projectionMetrics {
+		wg.Add(1)
+
+		go func(projection config.ProjectionMetrics, idx int) {
+			defer wg.Done()
+
+			logger := log.WithFields(log.Fields{"projection": projection.Projection, "partition": projection.Partition, "source": projection.Source})
+			logger.Debug("Getting projection metrics")
+
+			document, err := client.getProjectionDocument(ctx, projection)
+			if err != nil {
+				logger.WithError(err).Warn("Error when reading projection metrics")
+				return
+			}
+
+			values[idx] = projectionMetricValues(projection, document)
+		}(projection, i)
+	}
+
+	wg.Wait()
+
+	var result []ProjectionMetricValue
+	for _, projectionValues := range values {
+		result = append(result, projectionValues...)
+	}
+
+	return result
+}
+
+// getProjectionDocument returns decoded state or result of a projection, nil when projection has none yet
+func (client *EventStoreStatsClient) getProjectionDocument(ctx context.Context, projection config.ProjectionMetrics) (any, error) {
+	path := fmt.Sprintf("/projection/%s/%s", url.PathEscape(projection.Projection), projection.Source)
+	if projection.Partition != "" {
+		path += "?partition=" + url.QueryEscape(projection.Partition)
+	}
+
+	jsonBytes, err := client.esHTTPGet(ctx, path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if jsonBytes == nil {
+		return nil, fmt.Errorf("projection %s not found", projection.Projection)
+	}
+
+	if len(bytes.TrimSpace(jsonBytes)) == 0 {
+		return nil, nil
+	}
+
+	var document any
+	if err := json.Unmarshal(jsonBytes, &document); err != nil {
+		return nil, fmt.Errorf("projection %s is not JSON: %w", projection.Source, err)
+	}
+
+	return document, nil
+}
+
+func projectionMetricValues(projection config.ProjectionMetrics, document any) []ProjectionMetricValue {
+	logger := log.WithField("projection", projection.Projection)
+
+	var values []ProjectionMetricValue
+	for _, metric := range projection.Metrics {
+		for _, value := range jsonMetricValues(metric, document, logger) {
+			values = append(values, ProjectionMetricValue{
+				Metric:      metric,
+				Projection:  projection.Projection,
+				Partition:   projection.Partition,
+				LabelValues: value.labelValues,
+				Value:       value.value,
+			})
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_ProjectionMetricValues(t *testing.T) {
+	var document any
+	if err := json.Unmarshal([]byte(`{"total": 12, "regions": {"us": {"count": 5}, "eu": {"count": "many"}}}`), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	total := config.JSONMetric{Name: "orders_total", Path: "$.total"}
+	perRegion := config.JSONMetric{Name: "orders_per_region", Path: "$.regions.*.count", Labels: []string{"region"}}
+	missing := config.JSONMetric{Name: "orders_missing", Path: "$.missing"}
+	projection := config.ProjectionMetrics{Projection: "orders", Partition: "customer-1", Metrics: []config.JSONMetric{total, perRegion, missing}}
+
+	expected := []ProjectionMetricValue{
+		{Metric: total, Projection: "orders", Partition: "customer-1", Value: 12},
+		{Metric: perRegion, Projection: "orders", Partition: "customer-1", LabelValues: []string{"us"}, Value: 5},
+	}
+
+	if diff := cmp.Diff(projectionMetricValues(projection, document), expected); diff != "" {
+		t.Errorf("wrong projection metric values, diff: %v", diff)
+	}
+}
+
+func Test_ProjectionMetricValues_NoDocument(t *testing.T) {
+	projection := config.ProjectionMetrics{Projection: "orders", Metrics: []config.JSONMetric{{Name: "orders_total", Path: "$.total"}}}
+
+	if values := projectionMetricValues(projection, nil); len(values) != 0 {
+		t.Errorf("expected no values when projection has no state, got %v", values)
+	}
+}
@@ -2,6 +2,12 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	log "github.com/sirupsen/logrus"
 )
 
 type projectionStatsEnvelope struct {
@@ -11,15 +17,63 @@ type projectionStatsEnvelope struct {
 type ProjectionStats struct {
 	EffectiveName               string  `json:"effectiveName"`
 	Status                      string  `json:"status"`
+	StateReason                 string  `json:"stateReason"`
+	Mode                        string  `json:"mode"`
+	Enabled                     *bool   `json:"enabled"` // nil when not reported by the source
+	CheckpointStatus            string  `json:"checkpointStatus"`
 	Progress                    float64 `json:"progress"`
 	EventsProcessedAfterRestart int64   `json:"eventsProcessedAfterRestart"`
 }
 
-func (client *EventStoreStatsClient) getProjectionStats(ctx context.Context) ([]ProjectionStats, error) {
-	envelope, err := esHTTPGetAndParse[projectionStatsEnvelope](ctx, client, "/projections/all-non-transient", true)
+// getProjectionStats returns stats of projections, together with information whether projections are enabled on the server
+func (client *EventStoreStatsClient) getProjectionStats(ctx context.Context, getEsInfo func() (*EsInfo, error)) ([]ProjectionStats, bool, error) {
+	info, err := getEsInfo()
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+
+	if !info.Features.Projections {
+		return nil, false, nil
+	}
+
+	source := client.config().ProjectionStatsSource
+	if source == config.StatsSourceGRPC {
+		projections, err := client.getProjectionStatsOverGrpc(ctx)
+		return projections, err == nil, err
+	}
+
+	path := "/projections/all-non-transient"
+	if client.config().IncludeOneTimeProjections {
+		path = "/projections/any"
+	}
+
+	// AtomPub feature doesn't tell whether HTTP projection endpoints are available, so in auto mode they are
+	// always tried first and gRPC is used when they are missing or not accessible
+	jsonBytes, err := client.esHTTPGet(ctx, path, true)
+	unavailable := (err == nil && jsonBytes == nil) || isHTTPStatusError(err, http.StatusUnauthorized, http.StatusForbidden)
+	if source != config.StatsSourceHTTP && unavailable {
+		log.WithError(err).Debug("HTTP projection endpoints are unavailable, getting projection stats over grpc")
+		projections, grpcErr := client.getProjectionStatsOverGrpc(ctx)
+		if grpcErr != nil {
+			if err != nil {
+				return nil, false, errors.Join(err, grpcErr)
+			}
+
+			log.WithError(grpcErr).Warn("Error when getting projection stats over grpc, reporting projections as disabled")
+			return nil, false, nil
+		}
+
+		return projections, true, nil
+	}
+
+	if err != nil || jsonBytes == nil {
+		return nil, false, err
+	}
+
+	var envelope projectionStatsEnvelope
+	if err := json.Unmarshal(jsonBytes, &envelope); err != nil {
+		return nil, false, err
 	}
 
-	return envelope.Projections, nil
+	return envelope.Projections, true, nil
 }
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+)
+
+// getProjectionStatsOverGrpc lists projections with gRPC projection management API, continuous projections only,
+// unless one-time projections are included
+func (client *EventStoreStatsClient) getProjectionStatsOverGrpc(ctx context.Context) ([]ProjectionStats, error) {
+	grpcClient, ctx, err := client.getGrpcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projectionClient := esdb.NewProjectionClientFromExistingClient(grpcClient)
+	defer projectionClient.Close()
+
+	var statuses []esdb.ProjectionStatus
+	if client.config().IncludeOneTimeProjections {
+		statuses, err = projectionClient.ListAll(ctx, esdb.GenericProjectionOptions{})
+	} else {
+		statuses, err = projectionClient.ListContinuous(ctx, esdb.GenericProjectionOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	projections := make([]ProjectionStats, 0, len(statuses))
+	for _, status := range statuses {
+		projections = append(projections, projectionStatsFromStatus(status))
+	}
+
+	return projections, nil
+}
+
+func projectionStatsFromStatus(status esdb.ProjectionStatus) ProjectionStats {
+	return ProjectionStats{
+		EffectiveName:               status.EffectiveName,
+		Status:                      status.Status,
+		StateReason:                 status.StateReason,
+		Mode:                        status.Mode,
+		CheckpointStatus:            status.CheckpointStatus,
+		Progress:                    float64(status.Progress),
+		EventsProcessedAfterRestart: status.EventsProcessedAfterRestart,
+	}
+}
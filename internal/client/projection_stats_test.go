@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_ProjectionStats_ProjectionsDisabled(t *testing.T) {
+	client := New(&config.Config{EventStoreURL: "http://localhost:2113", ProjectionStatsSource: config.StatsSourceAuto})
+
+	projections, enabled, err := client.getProjectionStats(context.Background(), func() (*EsInfo, error) {
+		return &EsInfo{Features: Features{Projections: false, AtomPub: true}}, nil
+	})
+
+	if err != nil || enabled || projections != nil {
+		t.Errorf("Expected projections to be reported as disabled, got %v, %t, %v", projections, enabled, err)
+	}
+}
+
+func Test_ProjectionStats_OverHTTP(t *testing.T) {
+	tests := []struct {
+		name                      string
+		includeOneTimeProjections bool
+		expectedPath              string
+	}{
+		{name: "non-transient projections", includeOneTimeProjections: false, expectedPath: "/projections/all-non-transient"},
+		{name: "one-time and transient projections included", includeOneTimeProjections: true, expectedPath: "/projections/any"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				_, _ = w.Write([]byte(`{"projections": [{"effectiveName": "$by_category", "status": "Running"}]}`))
+			}))
+			defer server.Close()
+
+			client := New(&config.Config{EventStoreURL: server.URL, ProjectionStatsSource: config.StatsSourceHTTP, IncludeOneTimeProjections: test.includeOneTimeProjections})
+
+			projections, enabled, err := client.getProjectionStats(context.Background(), func() (*EsInfo, error) {
+				return &EsInfo{Features: Features{Projections: true}}, nil
+			})
+
+			if err != nil || !enabled || len(projections) != 1 || projections[0].EffectiveName != "$by_category" {
+				t.Errorf("Unexpected projection stats %v, %t, %v", projections, enabled, err)
+			}
+			if path != test.expectedPath {
+				t.Errorf("Expected request to %s, got %s", test.expectedPath, path)
+			}
+		})
+	}
+}
+
+func Test_ProjectionStats_HTTPEndpointNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client := New(&config.Config{EventStoreURL: server.URL, ProjectionStatsSource: config.StatsSourceHTTP})
+
+	projections, enabled, err := client.getProjectionStats(context.Background(), func() (*EsInfo, error) {
+		return &EsInfo{Features: Features{Projections: true, AtomPub: true}}, nil
+	})
+
+	if err != nil || enabled || projections != nil {
+		t.Errorf("Expected projections to be reported as disabled, got %v, %t, %v", projections, enabled, err)
+	}
+}
+
+func Test_ProjectionStats_AutoUsesHTTPWithoutAtomPub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"projections": [{"effectiveName": "$by_category", "status": "Running"}]}`))
+	}))
+	defer server.Close()
+
+	client := New(&config.Config{EventStoreURL: server.URL, ProjectionStatsSource: config.StatsSourceAuto})
+
+	projections, enabled, err := client.getProjectionStats(context.Background(), func() (*EsInfo, error) {
+		return &EsInfo{Features: Features{Projections: true, AtomPub: false}}, nil
+	})
+
+	if err != nil || !enabled || len(projections) != 1 || projections[0].EffectiveName != "$by_category" {
+		t.Errorf("Expected projection stats read over HTTP, got %v, %t, %v", projections, enabled, err)
+	}
+}
+
+func Test_ProjectionStats_AutoFallsBackToGrpcWhenHTTPUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		source     string
+		expectGrpc bool
+	}{
+		{name: "auto", source: config.StatsSourceAuto, expectGrpc: true},
+		{name: "http", source: config.StatsSourceHTTP, expectGrpc: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := New(&config.Config{EventStoreURL: server.URL, ProjectionStatsSource: test.source, Timeout: time.Second})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			_, enabled, err := client.getProjectionStats(ctx, func() (*EsInfo, error) {
+				return &EsInfo{Features: Features{Projections: true, AtomPub: true}}, nil
+			})
+
+			if err == nil || enabled {
+				t.Fatalf("Expected error, got %t, %v", enabled, err)
+			}
+			if !isHTTPStatusError(err, http.StatusUnauthorized) {
+				t.Errorf("Expected HTTP status error, got %v", err)
+			}
+			if _, usedGrpc := err.(interface{ Unwrap() []error }); usedGrpc != test.expectGrpc {
+				t.Errorf("Expected gRPC fallback %t, got error %v", test.expectGrpc, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ProjectionState is the state of a projection, parsed from status reported by the server
+type ProjectionState string
+
+const (
+	ProjectionStateCreating       ProjectionState = "Creating"
+	ProjectionStateLoading        ProjectionState = "Loading"
+	ProjectionStateLoaded         ProjectionState = "Loaded"
+	ProjectionStatePreparing      ProjectionState = "Preparing"
+	ProjectionStatePrepared       ProjectionState = "Prepared"
+	ProjectionStateStarting       ProjectionState = "Starting"
+	ProjectionStateLoadingStopped ProjectionState = "LoadingStopped"
+	ProjectionStateRunning        ProjectionState = "Running"
+	ProjectionStateStopping       ProjectionState = "Stopping"
+	ProjectionStateAborting       ProjectionState = "Aborting"
+	ProjectionStateStopped        ProjectionState = "Stopped"
+	ProjectionStateCompleted      ProjectionState = "Completed"
+	ProjectionStateAborted        ProjectionState = "Aborted"
+	ProjectionStateFaulted        ProjectionState = "Faulted"
+	ProjectionStateDeleting       ProjectionState = "Deleting"
+	ProjectionStateUnknown        ProjectionState = "Unknown"
+)
+
+// ProjectionStates lists all states, including unknown state for statuses that could not be parsed
+var ProjectionStates = []ProjectionState{
+	ProjectionStateCreating,
+	ProjectionStateLoading,
+	ProjectionStateLoaded,
+	ProjectionStatePreparing,
+	ProjectionStatePrepared,
+	ProjectionStateStarting,
+	ProjectionStateLoadingStopped,
+	ProjectionStateRunning,
+	ProjectionStateStopping,
+	ProjectionStateAborting,
+	ProjectionStateStopped,
+	ProjectionStateCompleted,
+	ProjectionStateAborted,
+	ProjectionStateFaulted,
+	ProjectionStateDeleting,
+	ProjectionStateUnknown,
+}
+
+const maxFaultReasonLength = 100
+
+// ParseProjectionState extracts state from projection status, status consists of the state optionally followed
+// by details, e.g. "Faulted (Enabled)", "Aborted/Stopped" or "Completed/Stopped/Writing results"
+func ParseProjectionState(status string) ProjectionState {
+	state, _, _ := strings.Cut(strings.TrimSpace(status), "/")
+	state, _, _ = strings.Cut(state, " ")
+
+	for _, known := range ProjectionStates {
+		if known != ProjectionStateUnknown && strings.EqualFold(state, string(known)) {
+			return known
+		}
+	}
+
+	return ProjectionStateUnknown
+}
+
+func (projection ProjectionStats) State() ProjectionState {
+	return ParseProjectionState(projection.Status)
+}
+
+// FaultReason returns reason of projection fault, sanitised to be used as label value, or empty string
+// when projection is not faulted
+func (projection ProjectionStats) FaultReason() string {
+	if projection.State() != ProjectionStateFaulted {
+		return ""
+	}
+
+	// reasons contain exception messages, possibly with stack traces
+	reason := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return ' '
+		}
+		return r
+	}, projection.StateReason)
+	reason = strings.Join(strings.Fields(reason), " ")
+
+	if runes := []rune(reason); len(runes) > maxFaultReasonLength {
+		reason = string(runes[:maxFaultReasonLength])
+	}
+
+	return reason
+}
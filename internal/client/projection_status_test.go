@@ -0,0 +1,47 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ParseProjectionState(t *testing.T) {
+	tests := []struct {
+		status string
+		want   ProjectionState
+	}{
+		{status: "Running", want: ProjectionStateRunning},
+		{status: "Stopped", want: ProjectionStateStopped},
+		{status: "Faulted (Enabled)", want: ProjectionStateFaulted},
+		{status: "Aborted/Stopped", want: ProjectionStateAborted},
+		{status: "Completed/Stopped/Writing results", want: ProjectionStateCompleted},
+		{status: "Preparing", want: ProjectionStatePreparing},
+		{status: "Stopping", want: ProjectionStateStopping},
+		{status: "LoadingStopped", want: ProjectionStateLoadingStopped},
+		{status: "", want: ProjectionStateUnknown},
+		{status: "Sleeping", want: ProjectionStateUnknown},
+	}
+
+	for _, test := range tests {
+		if got := ParseProjectionState(test.status); got != test.want {
+			t.Errorf("Expected status %q to be parsed as %s, got %s", test.status, test.want, got)
+		}
+	}
+}
+
+func Test_ProjectionFaultReason(t *testing.T) {
+	faulted := ProjectionStats{Status: "Faulted (Enabled)", StateReason: "The projection failed:\r\n\tSomething\x00 went wrong " + strings.Repeat("x", 200)}
+
+	reason := faulted.FaultReason()
+	if !strings.HasPrefix(reason, "The projection failed: Something went wrong x") {
+		t.Errorf("Expected sanitised reason, got %q", reason)
+	}
+	if len([]rune(reason)) != maxFaultReasonLength {
+		t.Errorf("Expected reason to be truncated to %d characters, got %d", maxFaultReasonLength, len([]rune(reason)))
+	}
+
+	running := ProjectionStats{Status: "Running", StateReason: "leftover reason"}
+	if reason := running.FaultReason(); reason != "" {
+		t.Errorf("Expected no fault reason for running projection, got %q", reason)
+	}
+}
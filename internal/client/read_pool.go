@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReadQueueWaitBuckets are upper bounds of read queue wait time histogram buckets, in seconds
+var ReadQueueWaitBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// ReadQueueWaitStats is a cumulative histogram of time reads spent waiting for a free slot
+type ReadQueueWaitStats struct {
+	Count   uint64
+	Sum     float64
+	Buckets map[float64]uint64
+}
+
+// readPool bounds the number of stream and parked messages reads executed at the same time,
+// slots are shared by all sections of a scrape as well as by concurrent scrapes
+type readPool struct {
+	slots chan struct{}
+	waits *readQueueWaits
+}
+
+func newReadPool(maxConcurrentReads uint, waits *readQueueWaits) *readPool {
+	return &readPool{
+		slots: make(chan struct{}, maxConcurrentReads),
+		waits: waits,
+	}
+}
+
+func (pool *readPool) size() uint {
+	return uint(cap(pool.slots))
+}
+
+// run executes read once a slot is free, with context limited by read timeout
+func (pool *readPool) run(ctx context.Context, timeout time.Duration, read func(ctx context.Context) error) error {
+	queuedAt := time.Now()
+
+	select {
+	case pool.slots <- struct{}{}:
+	case <-ctx.Done():
+		pool.waits.observe(time.Since(queuedAt))
+		return fmt.Errorf("error while waiting for a free read slot: %w", ctx.Err())
+	}
+	defer func() { <-pool.slots }()
+
+	pool.waits.observe(time.Since(queuedAt))
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return read(ctx)
+}
+
+type readQueueWaits struct {
+	mutex   sync.Mutex
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+func newReadQueueWaits() *readQueueWaits {
+	return &readQueueWaits{buckets: make([]uint64, len(ReadQueueWaitBuckets))}
+}
+
+func (waits *readQueueWaits) observe(wait time.Duration) {
+	seconds := wait.Seconds()
+
+	waits.mutex.Lock()
+	defer waits.mutex.Unlock()
+
+	waits.count++
+	waits.sum += seconds
+	for i, upperBound := range ReadQueueWaitBuckets {
+		if seconds <= upperBound {
+			waits.buckets[i]++
+		}
+	}
+}
+
+func (waits *readQueueWaits) stats() ReadQueueWaitStats {
+	waits.mutex.Lock()
+	defer waits.mutex.Unlock()
+
+	buckets := make(map[float64]uint64, len(ReadQueueWaitBuckets))
+	for i, upperBound := range ReadQueueWaitBuckets {
+		buckets[upperBound] = waits.buckets[i]
+	}
+
+	return ReadQueueWaitStats{Count: waits.count, Sum: waits.sum, Buckets: buckets}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ReadPool_LimitsConcurrentReads(t *testing.T) {
+	pool := newReadPool(2, newReadQueueWaits())
+
+	var running, maxRunning atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = pool.run(context.Background(), time.Second, func(context.Context) error {
+				current := running.Add(1)
+				for {
+					previous := maxRunning.Load()
+					if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning.Load() != 2 {
+		t.Errorf("Expected at most 2 concurrent reads, got %d", maxRunning.Load())
+	}
+
+	stats := pool.waits.stats()
+	if stats.Count != 10 {
+		t.Errorf("Expected 10 observed waits, got %d", stats.Count)
+	}
+	if stats.Buckets[ReadQueueWaitBuckets[len(ReadQueueWaitBuckets)-1]] != 10 {
+		t.Errorf("Expected all waits in the largest bucket, got %v", stats.Buckets)
+	}
+}
+
+func Test_ReadPool_AppliesReadTimeout(t *testing.T) {
+	pool := newReadPool(1, newReadQueueWaits())
+
+	err := pool.run(context.Background(), 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func Test_ReadPool_StopsWaitingWhenContextDone(t *testing.T) {
+	pool := newReadPool(1, newReadQueueWaits())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = pool.run(context.Background(), time.Second, func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	executed := false
+	err := pool.run(ctx, time.Second, func(context.Context) error {
+		executed = true
+		return nil
+	})
+
+	if err == nil || executed {
+		t.Errorf("Expected read not to be executed, got error %v", err)
+	}
+}
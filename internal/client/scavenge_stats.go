@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	scavengesStream            = "$scavenges"
+	scavengeStartedEventType   = "$scavengeStarted"
+	scavengeCompletedEventType = "$scavengeCompleted"
+
+	// number of most recent events in $scavenges stream to look at, should cover latest scavenge of every node
+	scavengeEventsToRead = 50
+)
+
+type ScavengeStats struct {
+	NodeEndpoint    string
+	ScavengeID      string
+	StartTime       time.Time
+	EndTime         time.Time
+	TimeTaken       time.Duration
+	Result          string
+	SpaceSavedBytes int64
+	IsRunning       bool
+}
+
+type scavengeEvent struct {
+	EventType    string
+	CreatedDate  time.Time
+	ScavengeID   string         `json:"scavengeId"`
+	NodeEndpoint string         `json:"nodeEndpoint"`
+	Result       string         `json:"result"`
+	TimeTaken    DotNetTimeSpan `json:"timeTaken"`
+	SpaceSaved   int64          `json:"spaceSaved"`
+}
+
+func (client *EventStoreStatsClient) getScavengeStats(ctx context.Context) ([]ScavengeStats, error) {
+	if !client.config().EnableScavengeStats {
+		return []ScavengeStats{}, nil
+	}
+
+	grpcClient, ctx, err := client.getGrpcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer grpcClient.Close()
+
+	events, err := readScavengeEvents(ctx, grpcClient, scavengesStream, esdb.ReadStreamOptions{Direction: esdb.Backwards, From: esdb.End{}, ResolveLinkTos: true}, scavengeEventsToRead)
+	if isStreamNotFound(err) {
+		return []ScavengeStats{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	scavenges := summarizeScavenges(events)
+
+	for i := range scavenges {
+		if scavenges[i].StartTime.IsZero() {
+			addScavengeStartTimeFromScavengeStream(ctx, grpcClient, &scavenges[i])
+		}
+	}
+
+	return scavenges, nil
+}
+
+// summarizeScavenges finds the latest scavenge of each node, based on scavenge events ordered from newest to oldest
+func summarizeScavenges(events []scavengeEvent) []ScavengeStats {
+	scavenges := make([]ScavengeStats, 0)
+	scavengeIdxByNode := make(map[string]int)
+
+	for _, event := range events {
+		idx, found := scavengeIdxByNode[event.NodeEndpoint]
+		if !found {
+			scavenges = append(scavenges, ScavengeStats{NodeEndpoint: event.NodeEndpoint, ScavengeID: event.ScavengeID, IsRunning: true})
+			idx = len(scavenges) - 1
+			scavengeIdxByNode[event.NodeEndpoint] = idx
+		}
+
+		scavenge := &scavenges[idx]
+		if scavenge.ScavengeID != event.ScavengeID {
+			continue // older scavenge of the same node
+		}
+
+		switch event.EventType {
+		case scavengeStartedEventType:
+			scavenge.StartTime = event.CreatedDate
+		case scavengeCompletedEventType:
+			scavenge.IsRunning = false
+			scavenge.EndTime = event.CreatedDate
+			scavenge.TimeTaken = event.TimeTaken.Duration
+			scavenge.Result = event.Result
+			scavenge.SpaceSavedBytes = event.SpaceSaved
+		}
+	}
+
+	return scavenges
+}
+
+// older scavenge started events may already be outside of the $scavenges read window, but they are also
+// the first event in the stream dedicated to the scavenge
+func addScavengeStartTimeFromScavengeStream(ctx context.Context, grpcClient *esdb.Client, scavenge *ScavengeStats) {
+	events, err := readScavengeEvents(ctx, grpcClient, scavengesStream+"-"+scavenge.ScavengeID, esdb.ReadStreamOptions{Direction: esdb.Forwards, From: esdb.Start{}}, 1)
+	if err != nil {
+		log.WithError(err).WithField("scavengeId", scavenge.ScavengeID).Warn("Error when reading scavenge stream")
+		return
+	}
+
+	if len(events) == 1 && events[0].EventType == scavengeStartedEventType {
+		scavenge.StartTime = events[0].CreatedDate
+	} else if !scavenge.IsRunning && scavenge.TimeTaken > 0 {
+		scavenge.StartTime = scavenge.EndTime.Add(-scavenge.TimeTaken)
+	}
+}
+
+func readScavengeEvents(ctx context.Context, grpcClient *esdb.Client, stream string, options esdb.ReadStreamOptions, count uint64) ([]scavengeEvent, error) {
+	resolvedEvents, err := readEvents(ctx, grpcClient, stream, options, count)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]scavengeEvent, 0, len(resolvedEvents))
+	for _, resolvedEvent := range resolvedEvents {
+		if resolvedEvent.Event == nil {
+			continue // link to a scavenge stream that was already deleted
+		}
+
+		event := scavengeEvent{}
+		if err := json.Unmarshal(resolvedEvent.Event.Data, &event); err != nil {
+			log.WithError(err).WithField("streamId", stream).Warn("Error when parsing scavenge event")
+			continue
+		}
+		event.EventType = resolvedEvent.Event.EventType
+		event.CreatedDate = resolvedEvent.Event.CreatedDate
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_SummarizeScavenges(t *testing.T) {
+	start := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+
+	// newest first, as read backwards from $scavenges stream
+	events := []scavengeEvent{
+		{EventType: scavengeStartedEventType, CreatedDate: start.Add(2 * time.Hour), ScavengeID: "b2", NodeEndpoint: "node-b"},
+		{EventType: scavengeCompletedEventType, CreatedDate: start.Add(time.Hour), ScavengeID: "a1", NodeEndpoint: "node-a",
+			Result: "Success", TimeTaken: DotNetTimeSpan{time.Hour}, SpaceSaved: 1024},
+		{EventType: scavengeCompletedEventType, CreatedDate: start.Add(30 * time.Minute), ScavengeID: "b1", NodeEndpoint: "node-b",
+			Result: "Failed"},
+		{EventType: scavengeStartedEventType, CreatedDate: start, ScavengeID: "a1", NodeEndpoint: "node-a"},
+	}
+
+	scavenges := summarizeScavenges(events)
+
+	if len(scavenges) != 2 {
+		t.Fatalf("Expected scavenges of 2 nodes, got %d", len(scavenges))
+	}
+
+	running := scavenges[0]
+	if running.NodeEndpoint != "node-b" || running.ScavengeID != "b2" || !running.IsRunning || !running.StartTime.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Unexpected running scavenge %+v", running)
+	}
+
+	completed := scavenges[1]
+	if completed.NodeEndpoint != "node-a" || completed.IsRunning || completed.Result != "Success" || completed.SpaceSavedBytes != 1024 ||
+		!completed.StartTime.Equal(start) || !completed.EndTime.Equal(start.Add(time.Hour)) || completed.TimeTaken != time.Hour {
+		t.Errorf("Unexpected completed scavenge %+v", completed)
+	}
+}
@@ -11,10 +11,29 @@ type ServerStats struct {
 }
 
 type ProcessStats struct {
-	CPU         float64     `json:"cpu"`
-	MemoryBytes int64       `json:"mem"`
-	DiskIo      DiskIoStats `json:"diskIo"`
-	TCP         TCPStats    `json:"tcp"`
+	StartTime            DotNetDateTime `json:"startTime"`
+	CPU                  float64        `json:"cpu"`
+	MemoryBytes          int64          `json:"mem"`
+	ThreadsCount         int64          `json:"threadsCount"`
+	ContentionsRate      float64        `json:"contentionsRate"`
+	ThrownExceptionsRate float64        `json:"thrownExceptionsRate"`
+	GC                   GCStats        `json:"gc"`
+	DiskIo               DiskIoStats    `json:"diskIo"`
+	TCP                  TCPStats       `json:"tcp"`
+}
+
+type GCStats struct {
+	AllocationSpeed   float64 `json:"allocationSpeed"`
+	FragmentationPct  float64 `json:"fragmentation"`
+	Gen0ItemsCount    int64   `json:"gen0ItemsCount"`
+	Gen0Size          int64   `json:"gen0Size"`
+	Gen1ItemsCount    int64   `json:"gen1ItemsCount"`
+	Gen1Size          int64   `json:"gen1Size"`
+	Gen2ItemsCount    int64   `json:"gen2ItemsCount"`
+	Gen2Size          int64   `json:"gen2Size"`
+	LargeHeapSize     int64   `json:"largeHeapSize"`
+	TimeInGcPct       float64 `json:"timeInGc"`
+	TotalBytesInHeaps int64   `json:"totalBytesInHeaps"`
 }
 
 type DiskIoStats struct {
@@ -49,13 +68,44 @@ type DriveStats struct {
 }
 
 type EsStats struct {
-	Queues map[string]QueueStats `json:"queue"`
+	Queues    map[string]QueueStats `json:"queue"`
+	Writer    WriterStats           `json:"writer"`
+	ReadIndex ReadIndexStats        `json:"readIndex"`
+}
+
+type WriterStats struct {
+	LastFlushSize       int64   `json:"lastFlushSize"`
+	LastFlushDelayMs    float64 `json:"lastFlushDelayMs"`
+	MeanFlushSize       float64 `json:"meanFlushSize"`
+	MeanFlushDelayMs    float64 `json:"meanFlushDelayMs"`
+	MaxFlushSize        int64   `json:"maxFlushSize"`
+	MaxFlushDelayMs     float64 `json:"maxFlushDelayMs"`
+	QueuedFlushMessages int64   `json:"queuedFlushMessages"`
+}
+
+type ReadIndexStats struct {
+	CachedRecord        int64 `json:"cachedRecord"`
+	NotCachedRecord     int64 `json:"notCachedRecord"`
+	CachedStreamInfo    int64 `json:"cachedStreamInfo"`
+	NotCachedStreamInfo int64 `json:"notCachedStreamInfo"`
+	CachedTransInfo     int64 `json:"cachedTransInfo"`
+	NotCachedTransInfo  int64 `json:"notCachedTransInfo"`
 }
 
 type QueueStats struct {
-	QueueName      string `json:"queueName"`
-	Length         int64  `json:"length"`
-	ItemsProcessed int64  `json:"totalItemsProcessed"`
+	QueueName                 string         `json:"queueName"`
+	GroupName                 string         `json:"groupName"`
+	Length                    int64          `json:"length"`
+	LengthCurrentTryPeak      int64          `json:"lengthCurrentTryPeak"`
+	LengthLifetimePeak        int64          `json:"lengthLifetimePeak"`
+	ItemsProcessed            int64          `json:"totalItemsProcessed"`
+	AvgItemsPerSecond         float64        `json:"avgItemsPerSecond"`
+	AvgProcessingTimeMs       float64        `json:"avgProcessingTime"`
+	IdleTimePercent           float64        `json:"idleTimePercent"`
+	CurrentIdleTime           DotNetTimeSpan `json:"currentIdleTime"`
+	CurrentItemProcessingTime DotNetTimeSpan `json:"currentItemProcessingTime"`
+	InProgressMessage         string         `json:"inProgressMessage"`
+	LastProcessedMessage      string         `json:"lastProcessedMessage"`
 }
 
 func (client *EventStoreStatsClient) getServerStats(ctx context.Context) (*ServerStats, error) {
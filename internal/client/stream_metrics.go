@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// StreamMetricValue is a value of user-defined metric read from the last event in a stream,
+// label values are in the order of metric label names
+type StreamMetricValue struct {
+	Metric      config.JSONMetric
+	Stream      string
+	LabelValues []string
+	Value       float64
+}
+
+// streamMetricsFromLastEvents returns values of stream metrics, streams that can't be read or whose last event
+// is not JSON are skipped so that a single stream doesn't fail the whole scrape
+func streamMetricsFromLastEvents(streamMetrics []config.StreamMetrics, lastEvents map[string]*lastEventRead) []StreamMetricValue {
+	var values []StreamMetricValue
+
+	for _, stream := range streamMetrics {
+		logger := log.WithFields(log.Fields{"streamId": stream.Stream, "source": stream.Source})
+
+		read := lastEvents[stream.Stream]
+		if read.err != nil {
+			logger.WithError(read.err).Warn("Error when reading stream metrics")
+			continue
+		}
+
+		document, err := lastEventDocument(read.event, stream)
+		if err != nil {
+			logger.WithError(err).Warn("Error when reading stream metrics")
+			continue
+		}
+
+		values = append(values, streamMetricValues(stream, document)...)
+	}
+
+	return values
+}
+
+// lastEventDocument returns decoded data or metadata of the last event in a stream, with links resolved
+func lastEventDocument(event *esdb.ResolvedEvent, stream config.StreamMetrics) (any, error) {
+	if event.Event == nil {
+		return nil, fmt.Errorf("last event of stream %s links to a deleted event", stream.Stream)
+	}
+
+	content := event.Event.Data
+	if stream.Source == config.StreamMetricsSourceMetadata {
+		content = event.Event.UserMetadata
+	}
+
+	var document any
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("%s of the last event is not JSON: %w", stream.Source, err)
+	}
+
+	return document, nil
+}
+
+func streamMetricValues(stream config.StreamMetrics, document any) []StreamMetricValue {
+	logger := log.WithField("streamId", stream.Stream)
+
+	var values []StreamMetricValue
+	for _, metric := range stream.Metrics {
+		for _, value := range jsonMetricValues(metric, document, logger) {
+			values = append(values, StreamMetricValue{
+				Metric:      metric,
+				Stream:      stream.Stream,
+				LabelValues: value.labelValues,
+				Value:       value.value,
+			})
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_StreamMetricValues(t *testing.T) {
+	var document any
+	if err := json.Unmarshal([]byte(`{"service": "billing", "position": 42, "healthy": true, "lag": {"p1": 3, "p2": 0}, "host": 1}`), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	position := config.JSONMetric{Name: "service_position", Path: "$.position", LabelFields: map[string]string{"service": "$.service", "host": "$.host"}}
+	healthy := config.JSONMetric{Name: "service_healthy", Path: "$.healthy"}
+	lag := config.JSONMetric{Name: "service_lag", Path: "$.lag.*", Labels: []string{"partition"}, LabelFields: map[string]string{"service": "$.service"}}
+	name := config.JSONMetric{Name: "service_name", Path: "$.service"}
+	stream := config.StreamMetrics{Stream: "heartbeats", Source: config.StreamMetricsSourceData, Metrics: []config.JSONMetric{position, healthy, lag, name}}
+
+	// label fields are sorted by label name, values that are not strings are reported as empty
+	expected := []StreamMetricValue{
+		{Metric: position, Stream: "heartbeats", LabelValues: []string{"", "billing"}, Value: 42},
+		{Metric: healthy, Stream: "heartbeats", Value: 1},
+		{Metric: lag, Stream: "heartbeats", LabelValues: []string{"p1", "billing"}, Value: 3},
+		{Metric: lag, Stream: "heartbeats", LabelValues: []string{"p2", "billing"}, Value: 0},
+	}
+
+	if diff := cmp.Diff(streamMetricValues(stream, document), expected); diff != "" {
+		t.Errorf("wrong stream metric values, diff: %v", diff)
+	}
+}
+
+func Test_StreamMetricsFromLastEvents(t *testing.T) {
+	metric := config.JSONMetric{Name: "service_position", Path: "$.position"}
+	streamMetrics := []config.StreamMetrics{
+		{Stream: "heartbeats", Source: config.StreamMetricsSourceData, Metrics: []config.JSONMetric{metric}},
+		{Stream: "heartbeats", Source: config.StreamMetricsSourceMetadata, Metrics: []config.JSONMetric{{Name: "service_lag", Path: "$.lag"}}},
+		{Stream: "missing", Source: config.StreamMetricsSourceData, Metrics: []config.JSONMetric{metric}},
+	}
+	lastEvents := map[string]*lastEventRead{
+		"heartbeats": {event: &esdb.ResolvedEvent{Event: &esdb.RecordedEvent{Data: []byte(`{"position": 15}`), UserMetadata: []byte("not json")}}},
+		"missing":    {err: errors.New("stream not found")},
+	}
+
+	expected := []StreamMetricValue{{Metric: metric, Stream: "heartbeats", Value: 15}}
+
+	if diff := cmp.Diff(streamMetricsFromLastEvents(streamMetrics, lastEvents), expected); diff != "" {
+		t.Errorf("wrong stream metric values, diff: %v", diff)
+	}
+}
+
+func Test_StreamStatsFromLastEvent(t *testing.T) {
+	link := &esdb.ResolvedEvent{
+		Event: &esdb.RecordedEvent{EventNumber: 3},
+		Link:  &esdb.RecordedEvent{EventNumber: 42},
+	}
+
+	if stats := streamStatsFromLastEvent("$ce-orders", &lastEventRead{event: link}); stats.LastEventNumber != 42 {
+		t.Errorf("Expected number of the link in the read stream, got %d", stats.LastEventNumber)
+	}
+	if stats := streamStatsFromLastEvent("missing", &lastEventRead{err: errors.New("not found")}); stats.LastEventNumber != -1 || stats.LastCommitPosition != -1 {
+		t.Errorf("Expected failed read to be reported as -1, got %+v", stats)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -14,90 +15,109 @@ type StreamStats struct {
 	LastEventNumber    int64
 }
 
-func (client *EventStoreStatsClient) getStreamStats(ctx context.Context) ([]StreamStats, error) {
-	streamStats, err := getStreamStatsFromEachStream(ctx, client)
-	if err != nil {
-		return nil, err
-	}
-
-	return streamStats, nil
+type lastEventRead struct {
+	event *esdb.ResolvedEvent
+	err   error
 }
 
-func getStreamStatsFromEachStream(ctx context.Context, client *EventStoreStatsClient) ([]StreamStats, error) {
-	if len(client.config.Streams) == 0 {
-		return make([]StreamStats, 0), nil
+// getStreamStats returns stats of streams and user-defined stream metrics, the last event of a stream
+// is read once even if the stream is used by both
+func (client *EventStoreStatsClient) getStreamStats(ctx context.Context) ([]StreamStats, []StreamMetricValue, error) {
+	streams := client.config().Streams
+	streamMetrics := client.config().StreamMetrics
+	if len(streams) == 0 && len(streamMetrics) == 0 {
+		return make([]StreamStats, 0), nil, nil
 	}
 
-	grpcClient, err := client.getGrpcClient()
+	grpcClient, ctx, err := client.getGrpcClient(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer grpcClient.Close()
 
-	streamStats := make([]StreamStats, len(client.config.Streams))
-	var wg sync.WaitGroup
-
-	for i, stream := range client.config.Streams {
-		wg.Add(1)
-
-		go func(stream string, idx int) {
-			defer wg.Done()
-
-			log.WithField("stream", stream).Debug("Getting stream stats")
-			if stats, getErr := getSingleStreamStats(ctx, grpcClient, stream); getErr == nil {
-				streamStats[idx] = stats
-			} else {
-				streamStats[idx] = StreamStats{EventStreamID: stream, LastCommitPosition: -1, LastEventNumber: -1}
-			}
+	lastEvents := client.readLastEvents(ctx, grpcClient, streams, streamMetrics)
 
-		}(stream, i)
+	streamStats := make([]StreamStats, len(streams))
+	for i, stream := range streams {
+		streamStats[i] = streamStatsFromLastEvent(stream, lastEvents[stream])
 	}
 
-	wg.Wait()
-
-	return streamStats, nil
+	return streamStats, streamMetricsFromLastEvents(streamMetrics, lastEvents), nil
 }
 
-func getSingleStreamStats(ctx context.Context, grpcClient *esdb.Client, stream string) (StreamStats, error) {
-	if stream == "$all" {
-		return getAllStreamStats(ctx, grpcClient)
+func (client *EventStoreStatsClient) readLastEvents(ctx context.Context, grpcClient *esdb.Client, streams []string, streamMetrics []config.StreamMetrics) map[string]*lastEventRead {
+	// links are resolved for streams used by stream metrics, so that their data can be read from e.g. $ce-<category>
+	resolveLinks := map[string]bool{}
+	for _, stream := range streams {
+		resolveLinks[stream] = false
+	}
+	for _, stream := range streamMetrics {
+		resolveLinks[stream.Stream] = true
 	}
 
-	return getRegularStreamStats(ctx, grpcClient, stream)
-}
+	lastEvents := make(map[string]*lastEventRead, len(resolveLinks))
+	for stream := range resolveLinks {
+		lastEvents[stream] = &lastEventRead{}
+	}
 
-func getAllStreamStats(ctx context.Context, grpcClient *esdb.Client) (StreamStats, error) {
-	event, err := readSingleEventFromAll(ctx, grpcClient, esdb.ReadAllOptions{Direction: esdb.Backwards, From: esdb.End{}})
+	var wg sync.WaitGroup
+	for stream, read := range lastEvents {
+		wg.Add(1)
 
-	if err != nil {
-		log.WithError(err).Error("Error when reading last event from $all stream")
+		go func(stream string, read *lastEventRead) {
+			defer wg.Done()
 
-		return StreamStats{}, err
+			log.WithField("stream", stream).Debug("Reading last event of stream")
+			read.err = client.runRead(ctx, func(ctx context.Context) (err error) {
+				read.event, err = readLastEvent(ctx, grpcClient, stream, resolveLinks[stream])
+				return err
+			})
+		}(stream, read)
 	}
 
-	return StreamStats{
-		EventStreamID:      "$all",
-		LastCommitPosition: int64(event.Event.Position.Commit), //nolint:gosec // TODO: fix this
-		LastEventNumber:    -1,
-	}, nil
+	wg.Wait()
 
+	return lastEvents
 }
 
-func getRegularStreamStats(ctx context.Context, grpcClient *esdb.Client, stream string) (StreamStats, error) {
+func readLastEvent(ctx context.Context, grpcClient *esdb.Client, stream string, resolveLinks bool) (*esdb.ResolvedEvent, error) {
+	if stream == "$all" {
+		event, err := readSingleEventFromAll(ctx, grpcClient, esdb.ReadAllOptions{Direction: esdb.Backwards, From: esdb.End{}})
+		if err != nil {
+			log.WithError(err).Error("Error when reading last event from $all stream")
+		}
+
+		return event, err
+	}
 
-	event, err := readSingleEvent(ctx, grpcClient, stream, esdb.ReadStreamOptions{Direction: esdb.Backwards, From: esdb.End{}})
+	event, err := readSingleEvent(ctx, grpcClient, stream, esdb.ReadStreamOptions{Direction: esdb.Backwards, From: esdb.End{}, ResolveLinkTos: resolveLinks})
 	if err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"streamId": stream,
 		}).Error("Error when reading last event from stream")
+	}
+
+	return event, err
+}
+
+func streamStatsFromLastEvent(stream string, read *lastEventRead) StreamStats {
+	if read.err != nil {
+		return StreamStats{EventStreamID: stream, LastCommitPosition: -1, LastEventNumber: -1}
+	}
 
-		return StreamStats{}, err
+	// original event is the link when links are resolved, its number is the position in this stream
+	event := read.event.OriginalEvent()
+	if stream == "$all" {
+		return StreamStats{
+			EventStreamID:      "$all",
+			LastCommitPosition: int64(event.Position.Commit), //nolint:gosec // TODO: fix this
+			LastEventNumber:    -1,
+		}
 	}
 
 	return StreamStats{
 		EventStreamID:      stream,
 		LastCommitPosition: -1,
-		LastEventNumber:    int64(event.Event.EventNumber), //nolint:gosec // TODO: fix this
-	}, nil
-
+		LastEventNumber:    int64(event.EventNumber), //nolint:gosec // TODO: fix this
+	}
 }
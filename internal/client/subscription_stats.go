@@ -25,16 +25,31 @@ type SubscriptionStats struct {
 	TotalInFlightMessages           int64  `json:"totalInFlightMessages"`
 	TotalNumberOfParkedMessages     int64
 	OldestParkedMessageAgeInSeconds float64
+	ParkedMessagesSource            string
+	ParkedMessagesAnalysis          *ParkedMessagesAnalysis
 }
 
-func (client *EventStoreStatsClient) getSubscriptionStats(ctx context.Context) ([]SubscriptionStats, error) {
-	subscriptions, err := esHTTPGetAndParse[[]SubscriptionStats](ctx, client, "/subscriptions", false)
+func (client *EventStoreStatsClient) getSubscriptionStats(ctx context.Context, getEsInfo func() (*EsInfo, error)) ([]SubscriptionStats, error) {
+	var subscriptions []SubscriptionStats
+	var parkedCounts map[subscriptionKey]int64
+	var err error
+
+	if client.usesGrpcSubscriptionStats(getEsInfo) {
+		subscriptions, parkedCounts, err = client.getSubscriptionStatsOverGrpc(ctx)
+	} else {
+		subscriptions, err = esHTTPGetAndParse[[]SubscriptionStats](ctx, client, "/subscriptions", false)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if client.config.EnableParkedMessagesStats {
-		client.addParkedMessagesStats(ctx, subscriptions)
+	if client.config().EnableParkedMessagesStats {
+		var esVersion EventStoreVersion
+		if info, err := getEsInfo(); err == nil {
+			esVersion = info.EsVersion
+		}
+
+		client.addParkedMessagesStats(ctx, subscriptions, esVersion, parkedCounts)
 	} else {
 		markParkedMessageStatsAsUnavailable(subscriptions)
 	}
@@ -49,18 +64,33 @@ func markParkedMessageStatsAsUnavailable(subscriptions []SubscriptionStats) {
 	}
 }
 
-func (client *EventStoreStatsClient) addParkedMessagesStats(ctx context.Context, subscriptions []SubscriptionStats) {
+// addParkedMessagesStats adds parked messages stats to subscriptions, parkedCounts may contain counts already
+// reported when listing subscriptions
+func (client *EventStoreStatsClient) addParkedMessagesStats(ctx context.Context, subscriptions []SubscriptionStats, esVersion EventStoreVersion, parkedCounts map[subscriptionKey]int64) {
+	parkedMessages := client.connection().parkedMessages
+
 	if len(subscriptions) == 0 {
+		parkedMessages.retain(nil)
 		return
 	}
 
-	grpcClient, err := client.getGrpcClient()
+	grpcClient, ctx, err := client.getGrpcClient(ctx)
 
 	if err != nil {
 		log.WithError(err).Error("Error when creating grpc client")
+		return
 	}
 	defer grpcClient.Close()
 
+	if !usesParkedMessagesCountFromSubscriptionInfo(esVersion) {
+		parkedCounts = nil
+	} else if parkedCounts == nil {
+		parkedCounts, err = client.getParkedMessagesCounts(ctx, grpcClient)
+		if err != nil {
+			log.WithError(err).Warn("Error when listing persistent subscriptions, falling back to reading parked messages streams")
+		}
+	}
+
 	var wg sync.WaitGroup
 
 	for i := range subscriptions {
@@ -71,20 +101,97 @@ func (client *EventStoreStatsClient) addParkedMessagesStats(ctx context.Context,
 
 			log.WithField("eventStreamId", subscription.EventStreamID).WithField("groupName", subscription.GroupName).Debug("Getting subscription parked message stats")
 
-			subscription.TotalNumberOfParkedMessages, subscription.OldestParkedMessageAgeInSeconds, _ =
-				getParkedMessagesStats(ctx, grpcClient, subscription.EventStreamID, subscription.GroupName)
+			parkedCount, hasParkedCount := parkedCounts[subscriptionKey{subscription.EventStreamID, subscription.GroupName}]
+			if hasParkedCount {
+				client.addParkedMessagesStatsFromCount(ctx, grpcClient, subscription, parkedCount)
+			} else {
+				client.addParkedMessagesStatsFromStream(ctx, grpcClient, subscription)
+			}
+
+			if subscription.TotalNumberOfParkedMessages > 0 && client.config().EnableParkedMessagesAnalysis {
+				client.addParkedMessagesAnalysis(ctx, grpcClient, subscription)
+			}
 		}(&subscriptions[i])
 	}
 
 	wg.Wait()
+
+	parkedStreamIDs := make(map[string]bool, len(subscriptions))
+	for _, subscription := range subscriptions {
+		parkedStreamIDs[parkedStreamID(subscription.EventStreamID, subscription.GroupName)] = true
+	}
+	parkedMessages.retain(parkedStreamIDs)
+}
+
+// addParkedMessagesStatsFromCount uses count reported by the server, failure to read the oldest message
+// only makes its age unavailable. The oldest message is cached by position range of parked messages stream
+// like when reading counts from the stream, as the count stays the same when one message is replayed
+// and another one is parked.
+func (client *EventStoreStatsClient) addParkedMessagesStatsFromCount(ctx context.Context, grpcClient *esdb.Client, subscription *SubscriptionStats, parkedCount int64) {
+	parkedMessages := client.connection().parkedMessages
+
+	subscription.ParkedMessagesSource = ParkedMessagesSourceSubscriptionInfo
+	subscription.TotalNumberOfParkedMessages, subscription.OldestParkedMessageAgeInSeconds = parkedCount, -1
+
+	if parkedCount <= 0 {
+		parkedMessages.remove(parkedStreamID(subscription.EventStreamID, subscription.GroupName))
+		return
+	}
+
+	var oldestAgeInSec float64
+	getErr := client.runRead(ctx, func(ctx context.Context) (err error) {
+		_, oldestAgeInSec, err = getParkedMessagesStats(ctx, grpcClient, parkedMessages, subscription.EventStreamID, subscription.GroupName)
+		return err
+	})
+	if getErr == nil {
+		subscription.OldestParkedMessageAgeInSeconds = oldestAgeInSec
+	}
+}
+
+func (client *EventStoreStatsClient) addParkedMessagesStatsFromStream(ctx context.Context, grpcClient *esdb.Client, subscription *SubscriptionStats) {
+	subscription.ParkedMessagesSource = ParkedMessagesSourceStream
+
+	var numParked int64
+	var oldestAgeInSec float64
+	getErr := client.runRead(ctx, func(ctx context.Context) (err error) {
+		numParked, oldestAgeInSec, err = getParkedMessagesStats(ctx, grpcClient, client.connection().parkedMessages, subscription.EventStreamID, subscription.GroupName)
+		return err
+	})
+	if getErr == nil {
+		subscription.TotalNumberOfParkedMessages, subscription.OldestParkedMessageAgeInSeconds = numParked, oldestAgeInSec
+	} else {
+		subscription.TotalNumberOfParkedMessages, subscription.OldestParkedMessageAgeInSeconds = -1, -1
+	}
+}
+
+// addParkedMessagesAnalysis runs analysis as a separate read, so that its failure doesn't affect basic parked messages stats
+func (client *EventStoreStatsClient) addParkedMessagesAnalysis(ctx context.Context, grpcClient *esdb.Client, subscription *SubscriptionStats) {
+	var analysis *ParkedMessagesAnalysis
+	getErr := client.runRead(ctx, func(ctx context.Context) (err error) {
+		analysis, err = client.getParkedMessagesAnalysis(ctx, grpcClient, subscription.EventStreamID, subscription.GroupName)
+		return err
+	})
+	if getErr != nil {
+		log.WithError(getErr).WithFields(log.Fields{
+			"eventStreamId": subscription.EventStreamID,
+			"groupName":     subscription.GroupName,
+		}).Warn("Error when analyzing parked messages")
+		return
+	}
+
+	subscription.ParkedMessagesAnalysis = analysis
 }
 
-func getParkedMessagesStats(ctx context.Context, grpc *esdb.Client, eventStreamID, groupName string) (numParked int64, oldestAgeInSec float64, err error) {
+func getParkedMessagesStats(ctx context.Context, grpc *esdb.Client, cache *parkedMessagesCache, eventStreamID, groupName string) (numParked int64, oldestAgeInSec float64, err error) {
 	oldestAgeInSec = -1
+	streamID := parkedStreamID(eventStreamID, groupName)
 
 	parkedMessageFound, lastEventNumber, err := getParkedMessagesLastEventNumber(ctx, grpc, eventStreamID, groupName)
 
-	if err != nil || !parkedMessageFound {
+	if err != nil {
+		return
+	} else if !parkedMessageFound {
+		cache.remove(streamID)
 		return
 	}
 
@@ -97,8 +204,19 @@ func getParkedMessagesStats(ctx context.Context, grpc *esdb.Client, eventStreamI
 	totalNumberOfParkedMessages := lastEventNumber + 1 - truncateBeforeValue // +1 because ids start from 0
 
 	if totalNumberOfParkedMessages > 0 {
-		oldestMessagePosition := lastEventNumber + 1 - totalNumberOfParkedMessages
-		oldestAgeInSec, _ = getOldestParkedMessageAgeInSeconds(ctx, grpc, eventStreamID, groupName, oldestMessagePosition)
+		oldestCreated, cached := cache.get(streamID, lastEventNumber, truncateBeforeValue)
+		if !cached {
+			oldestMessagePosition := lastEventNumber + 1 - totalNumberOfParkedMessages
+			oldestCreated, err = getOldestParkedMessageCreatedDate(ctx, grpc, eventStreamID, groupName, oldestMessagePosition)
+			if err == nil {
+				cache.set(streamID, parkedMessagesCacheEntry{lastEventNumber: lastEventNumber, truncateBefore: truncateBeforeValue, oldestCreated: oldestCreated})
+			}
+			err = nil
+		}
+
+		if !oldestCreated.IsZero() {
+			oldestAgeInSec = parkedMessageAgeInSeconds(oldestCreated)
+		}
 	}
 
 	numParked = int64(totalNumberOfParkedMessages)
@@ -106,26 +224,28 @@ func getParkedMessagesStats(ctx context.Context, grpc *esdb.Client, eventStreamI
 	return
 }
 
-func getOldestParkedMessageAgeInSeconds(ctx context.Context, grpcClient *esdb.Client, eventStreamID string, groupName string, oldestMessagePosition uint64) (float64, error) {
+func getOldestParkedMessageCreatedDate(ctx context.Context, grpcClient *esdb.Client, eventStreamID string, groupName string, oldestMessagePosition uint64) (time.Time, error) {
 	event, err := readSingleEvent(ctx, grpcClient, parkedStreamID(eventStreamID, groupName), esdb.ReadStreamOptions{Direction: esdb.Forwards, From: esdb.Revision(oldestMessagePosition)})
 
 	if errors.Is(err, io.EOF) {
-		return -1, nil
+		return time.Time{}, nil
 	} else if err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"eventStreamId": eventStreamID,
 			"groupName":     groupName,
 		}).Error("Error when getting parked messages stream.")
 
-		return 0, err
+		return time.Time{}, err
 	}
 
-	created := event.Event.CreatedDate
+	return event.Event.CreatedDate, nil
+}
+
+func parkedMessageAgeInSeconds(created time.Time) float64 {
 	loc, _ := time.LoadLocation("UTC")
 	timeNow := time.Now().In(loc)
-	age := float64(timeNow.Sub(created) / time.Second)
 
-	return age, nil
+	return float64(timeNow.Sub(created) / time.Second)
 }
 
 func getParkedMessagesLastEventNumber(ctx context.Context, grpcClient *esdb.Client, eventStreamID string, groupName string) (bool, uint64, error) {
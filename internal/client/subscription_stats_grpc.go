@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func (client *EventStoreStatsClient) usesGrpcSubscriptionStats(getEsInfo func() (*EsInfo, error)) bool {
+	switch client.config().SubscriptionStatsSource {
+	case config.StatsSourceGRPC:
+		return true
+	case config.StatsSourceHTTP:
+		return false
+	}
+
+	info, err := getEsInfo()
+	return err == nil && !info.Features.AtomPub
+}
+
+// getSubscriptionStatsOverGrpc lists persistent subscriptions with gRPC API, which is available when AtomPub is disabled,
+// parked messages counts reported in the listing are returned too
+func (client *EventStoreStatsClient) getSubscriptionStatsOverGrpc(ctx context.Context) ([]SubscriptionStats, map[subscriptionKey]int64, error) {
+	grpcClient, ctx, err := client.getGrpcClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer grpcClient.Close()
+
+	infos, err := grpcClient.ListAllPersistentSubscriptions(ctx, esdb.ListPersistentSubscriptionsOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscriptions := make([]SubscriptionStats, 0, len(infos))
+	parkedCounts := make(map[subscriptionKey]int64, len(infos))
+	for _, info := range infos {
+		subscriptions = append(subscriptions, subscriptionStatsFromInfo(info))
+		if info.Stats != nil {
+			parkedCounts[subscriptionKey{info.EventSource, info.GroupName}] = info.Stats.ParkedMessagesCount
+		}
+	}
+
+	return subscriptions, parkedCounts, nil
+}
+
+func subscriptionStatsFromInfo(info esdb.PersistentSubscriptionInfo) SubscriptionStats {
+	subscription := SubscriptionStats{
+		EventStreamID:            info.EventSource,
+		GroupName:                info.GroupName,
+		ConnectionCount:          int64(len(info.Connections)),
+		LastKnownEventNumber:     -1,
+		LastProcessedEventNumber: -1,
+	}
+
+	if stats := info.Stats; stats != nil {
+		subscription.TotalItemsProcessed = stats.TotalItems
+		subscription.TotalInFlightMessages = stats.TotalInFlightMessages
+		subscription.LastKnownEventNumber = revisionOrUnknown(stats.LastKnownEventRevision)
+		subscription.LastProcessedEventNumber = revisionOrUnknown(stats.LastCheckpointedEventRevision)
+		subscription.LastKnownEventPosition = formatEventPosition(stats.LastKnownPosition)
+		subscription.LastCheckpointedEventPosition = formatEventPosition(stats.LastCheckpointedPosition)
+	}
+
+	return subscription
+}
+
+func revisionOrUnknown(revision *uint64) int64 {
+	if revision == nil {
+		return -1
+	}
+
+	return int64(*revision) //nolint:gosec // TODO: fix this
+}
+
+// formatEventPosition formats position the same way as HTTP API, e.g. "C:1234/P:5678"
+func formatEventPosition(position *esdb.Position) string {
+	if position == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("C:%d/P:%d", position.Commit, position.Prepare)
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_SubscriptionStatsFromInfo(t *testing.T) {
+	lastKnown, lastCheckpointed := uint64(20), uint64(15)
+
+	tests := []struct {
+		name     string
+		info     esdb.PersistentSubscriptionInfo
+		expected SubscriptionStats
+	}{
+		{
+			name: "stream subscription",
+			info: esdb.PersistentSubscriptionInfo{
+				EventSource: "my-stream",
+				GroupName:   "group1",
+				Connections: []esdb.PersistentSubscriptionConnectionInfo{{}, {}},
+				Stats: &esdb.PersistentSubscriptionStats{
+					TotalItems:                    100,
+					TotalInFlightMessages:         3,
+					OutstandingMessagesCount:      5,
+					LastKnownEventRevision:        &lastKnown,
+					LastCheckpointedEventRevision: &lastCheckpointed,
+				},
+			},
+			expected: SubscriptionStats{
+				EventStreamID:            "my-stream",
+				GroupName:                "group1",
+				TotalItemsProcessed:      100,
+				ConnectionCount:          2,
+				LastKnownEventNumber:     20,
+				LastProcessedEventNumber: 15,
+				TotalInFlightMessages:    3,
+			},
+		},
+		{
+			name: "$all subscription",
+			info: esdb.PersistentSubscriptionInfo{
+				EventSource: "$all",
+				GroupName:   "group2",
+				Stats: &esdb.PersistentSubscriptionStats{
+					LastKnownPosition:        &esdb.Position{Commit: 2000, Prepare: 1999},
+					LastCheckpointedPosition: &esdb.Position{Commit: 1000, Prepare: 999},
+				},
+			},
+			expected: SubscriptionStats{
+				EventStreamID:                 "$all",
+				GroupName:                     "group2",
+				LastKnownEventNumber:          -1,
+				LastProcessedEventNumber:      -1,
+				LastKnownEventPosition:        "C:2000/P:1999",
+				LastCheckpointedEventPosition: "C:1000/P:999",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(subscriptionStatsFromInfo(test.info), test.expected); diff != "" {
+				t.Errorf("Unexpected subscription stats, diff: %v", diff)
+			}
+		})
+	}
+}
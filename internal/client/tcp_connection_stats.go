@@ -16,7 +16,7 @@ type TCPConnectionStats struct {
 }
 
 func (client *EventStoreStatsClient) getTCPConnectionStats(ctx context.Context) ([]TCPConnectionStats, error) {
-	if !client.config.EnableTCPConnectionStats {
+	if !client.config().EnableTCPConnectionStats {
 		return []TCPConnectionStats{}, nil
 	}
 
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"sync"
+	"time"
+)
+
+type TLSCertificateStats struct {
+	Member   string
+	Subject  string
+	Issuer   string
+	Serial   string
+	NotAfter time.Time
+}
+
+// peerCertificates keeps certificates presented to the stats HTTP client, so that they are reported without
+// separate connections to cluster members. Only certificates that passed verification of the HTTP client
+// are seen, certificates of members that the exporter doesn't call are not reported.
+type peerCertificates struct {
+	mutex      sync.Mutex
+	members    map[string]observedPeerCertificates
+	lastReport time.Time
+}
+
+type observedPeerCertificates struct {
+	certificates []*x509.Certificate
+	observedAt   time.Time
+}
+
+func newPeerCertificates() *peerCertificates {
+	return &peerCertificates{members: make(map[string]observedPeerCertificates)}
+}
+
+func (peers *peerCertificates) observe(member string, certificates []*x509.Certificate) {
+	if len(certificates) == 0 {
+		return
+	}
+
+	peers.mutex.Lock()
+	defer peers.mutex.Unlock()
+
+	peers.members[member] = observedPeerCertificates{certificates: certificates, observedAt: time.Now()}
+}
+
+// report returns certificates of members called since the previous report, members that are no longer
+// called (e.g. after another node was selected) are forgotten
+func (peers *peerCertificates) report() []TLSCertificateStats {
+	peers.mutex.Lock()
+	defer peers.mutex.Unlock()
+
+	stats := make([]TLSCertificateStats, 0)
+	for member, observed := range peers.members {
+		if observed.observedAt.Before(peers.lastReport) {
+			delete(peers.members, member)
+			continue
+		}
+
+		// the same certificate may be sent more than once in the chain
+		fingerprints := make(map[[sha256.Size]byte]bool, len(observed.certificates))
+		for _, certificate := range observed.certificates {
+			fingerprint := sha256.Sum256(certificate.Raw)
+			if fingerprints[fingerprint] {
+				continue
+			}
+			fingerprints[fingerprint] = true
+
+			stats = append(stats, TLSCertificateStats{
+				Member:   member,
+				Subject:  certificate.Subject.String(),
+				Issuer:   certificate.Issuer.String(),
+				Serial:   certificate.SerialNumber.Text(16),
+				NotAfter: certificate.NotAfter,
+			})
+		}
+	}
+	peers.lastReport = time.Now()
+
+	return stats
+}
+
+func (client *EventStoreStatsClient) getTLSCertificateStats() []TLSCertificateStats {
+	if !client.config().EnableTLSCertificateStats {
+		return []TLSCertificateStats{}
+	}
+
+	return client.connection().peerCertificates.report()
+}
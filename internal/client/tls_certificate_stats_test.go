@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_TLSCertificateStats_CapturedFromHTTPCalls(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	client := New(&config.Config{EventStoreURL: server.URL, InsecureSkipVerify: true, EnableTLSCertificateStats: true})
+
+	if certificates := client.getTLSCertificateStats(); len(certificates) != 0 {
+		t.Fatalf("Expected no certificates before any call, got %+v", certificates)
+	}
+
+	if _, err := client.esHTTPGet(context.Background(), "/gossip", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	certificates := client.getTLSCertificateStats()
+	if len(certificates) != 1 {
+		t.Fatalf("Expected 1 certificate, got %d", len(certificates))
+	}
+
+	certificate := certificates[0]
+	expected := server.Certificate()
+	if certificate.Member != strings.TrimPrefix(server.URL, "https://") || certificate.Subject != expected.Subject.String() ||
+		certificate.Issuer != expected.Issuer.String() || certificate.Serial != expected.SerialNumber.Text(16) ||
+		!certificate.NotAfter.Equal(expected.NotAfter) {
+		t.Errorf("Unexpected certificate stats %+v", certificate)
+	}
+}
+
+func Test_TLSCertificateStats_Disabled(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	client := New(&config.Config{EventStoreURL: server.URL, InsecureSkipVerify: true})
+
+	if _, err := client.esHTTPGet(context.Background(), "/gossip", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if certificates := client.getTLSCertificateStats(); len(certificates) != 0 {
+		t.Errorf("Expected no certificates, got %+v", certificates)
+	}
+}
+
+func Test_PeerCertificates_DeduplicatesCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	peers := newPeerCertificates()
+	peers.observe("node1:2113", []*x509.Certificate{server.Certificate(), server.Certificate()})
+	peers.observe("node2:2113", []*x509.Certificate{server.Certificate()})
+
+	stats := peers.report()
+	if len(stats) != 2 {
+		t.Fatalf("Expected one certificate per member, got %+v", stats)
+	}
+
+	for _, member := range []string{"node1:2113", "node2:2113"} {
+		count := 0
+		for _, certificate := range stats {
+			if certificate.Member == member {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 certificate of %s, got %d", member, count)
+		}
+	}
+}
+
+func Test_PeerCertificates_ForgetsMembersNotCalledSincePreviousReport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	peers := newPeerCertificates()
+	peers.observe("node1:2113", []*x509.Certificate{server.Certificate()})
+
+	if stats := peers.report(); len(stats) != 1 {
+		t.Fatalf("Expected certificate of node1, got %+v", stats)
+	}
+
+	time.Sleep(time.Millisecond)
+	peers.observe("node2:2113", []*x509.Certificate{server.Certificate()})
+
+	stats := peers.report()
+	if len(stats) != 1 || stats[0].Member != "node2:2113" {
+		t.Errorf("Expected only certificate of node2, got %+v", stats)
+	}
+}
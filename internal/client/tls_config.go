@@ -0,0 +1,139 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// tlsConfigLoader builds TLS config for EventStore connections from the configured
+// CA and client certificate files, and rebuilds it whenever any of the files changes
+type tlsConfigLoader struct {
+	mutex     sync.Mutex
+	config    *config.Config
+	modTimes  []time.Time
+	tlsConfig *tls.Config
+}
+
+func newTLSConfigLoader(config *config.Config) *tlsConfigLoader {
+	return &tlsConfigLoader{config: config}
+}
+
+func (loader *tlsConfigLoader) files() []string {
+	files := []string{}
+	for _, file := range []string{loader.config.EventStoreCAFile, loader.config.EventStoreClientCert, loader.config.EventStoreClientKey} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+func (loader *tlsConfigLoader) load() (*tls.Config, error) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+
+	modTimes, err := fileModTimes(loader.files())
+	if err == nil && loader.tlsConfig != nil && modTimesEqual(modTimes, loader.modTimes) {
+		return loader.tlsConfig, nil
+	}
+
+	var tlsConfig *tls.Config
+	if err == nil {
+		tlsConfig, err = loader.build()
+	}
+
+	if err != nil {
+		if loader.tlsConfig == nil {
+			return nil, err
+		}
+
+		// files may be in the middle of being replaced, keep using previous certificates
+		log.WithError(err).Warn("Error when reloading TLS certificates, using previously loaded ones")
+		return loader.tlsConfig, nil
+	}
+
+	if loader.tlsConfig != nil {
+		log.Info("TLS certificates changed, reloaded")
+	}
+
+	loader.tlsConfig = tlsConfig
+	loader.modTimes = modTimes
+
+	return tlsConfig, nil
+}
+
+func (loader *tlsConfigLoader) build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: loader.config.InsecureSkipVerify, // nolint: gosec
+	}
+
+	if loader.config.EventStoreCAFile != "" {
+		rootCAs, err := loadCAFile(loader.config.EventStoreCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if loader.config.EventStoreClientCert != "" {
+		certificate, err := tls.LoadX509KeyPair(loader.config.EventStoreClientCert, loader.config.EventStoreClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error while loading client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+func loadCAFile(file string) (*x509.CertPool, error) {
+	caPEM, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading CA file: %w", err)
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", file)
+	}
+
+	return rootCAs, nil
+}
+
+func fileModTimes(files []string) ([]time.Time, error) {
+	modTimes := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	return modTimes, nil
+}
+
+func modTimesEqual(a []time.Time, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_HTTPClientVerifiesServerWithCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	writeCertificatePEM(t, caFile, server.Certificate().Raw)
+
+	client := New(&config.Config{EventStoreURL: server.URL, EventStoreCAFile: caFile})
+
+	if _, err := client.esHTTPGet(context.Background(), "/", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func Test_TLSConfigReloadedWhenFilesChange(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	writeCertificatePEM(t, caFile, server.Certificate().Raw)
+
+	loader := newTLSConfigLoader(&config.Config{EventStoreCAFile: caFile})
+
+	first, err := loader.load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	unchanged, _ := loader.load()
+	if unchanged != first {
+		t.Error("Expected TLS config to be reused when files did not change")
+	}
+
+	if err := os.Chtimes(caFile, time.Now(), time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, _ := loader.load()
+	if reloaded == first {
+		t.Error("Expected TLS config to be rebuilt when files changed")
+	}
+
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(caFile, time.Now(), time.Now().Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	afterInvalid, err := loader.load()
+	if err != nil || afterInvalid != reloaded {
+		t.Errorf("Expected previous TLS config to be kept when files are invalid, got error %v", err)
+	}
+}
+
+func Test_TLSConfigErrorOnInvalidCAFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := newTLSConfigLoader(&config.Config{EventStoreCAFile: caFile})
+
+	if _, err := loader.load(); err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func writeCertificatePEM(t *testing.T, file string, der []byte) {
+	t.Helper()
+
+	certificatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(file, certificatePEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
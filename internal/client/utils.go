@@ -3,36 +3,69 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 
 	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
 	log "github.com/sirupsen/logrus"
 )
 
 func (client *EventStoreStatsClient) esHTTPGet(ctx context.Context, path string, acceptNotFound bool) (result []byte, err error) {
-	url := client.config.EventStoreURL + path
+	baseURL, err := client.getBaseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	conn := client.connection()
+	result, err = conn.httpGet(ctx, baseURL+path, acceptNotFound)
+
+	var urlErr *url.Error
+	if conn.nodes != nil && errors.As(err, &urlErr) {
+		// selected node may be gone, select again on next call
+		conn.nodes.reset()
+	}
+
+	return result, err
+}
+
+func (conn *connection) httpGet(ctx context.Context, url string, acceptNotFound bool) (result []byte, err error) {
 	log.WithField("url", url).Debug("GET request to EventStore")
 
+	authorization, err := conn.authenticator.authorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if client.config.EventStoreUser != "" && client.config.EventStorePassword != "" {
-		req.SetBasicAuth(client.config.EventStoreUser, client.config.EventStorePassword)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
 	}
 	req.Header.Add("Accept", "application/json")
-	response, err := client.httpClient.Do(req)
+	httpClient, err := conn.getHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
 
+	if response.TLS != nil {
+		conn.peerCertificates.observe(req.URL.Host, response.TLS.PeerCertificates)
+	}
+
 	if response.StatusCode == http.StatusNotFound && acceptNotFound {
 		return nil, nil
 	}
 
 	if response.StatusCode >= 400 {
-		return nil, fmt.Errorf("HTTP call to %s resulted in status code %d", url, response.StatusCode)
+		return nil, &httpStatusError{url: url, statusCode: response.StatusCode}
 	}
 
 	buf, err := io.ReadAll(response.Body)
@@ -43,6 +76,20 @@ func (client *EventStoreStatsClient) esHTTPGet(ctx context.Context, path string,
 	return buf, nil
 }
 
+type httpStatusError struct {
+	url        string
+	statusCode int
+}
+
+func (err *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP call to %s resulted in status code %d", err.url, err.statusCode)
+}
+
+func isHTTPStatusError(err error, statusCodes ...int) bool {
+	var statusErr *httpStatusError
+	return errors.As(err, &statusErr) && slices.Contains(statusCodes, statusErr.statusCode)
+}
+
 func esHTTPGetAndParse[TResponse any](ctx context.Context, client *EventStoreStatsClient, path string, acceptNotFound bool) (TResponse, error) {
 	var response TResponse
 
@@ -88,3 +135,31 @@ func readSingleEventFromAll(ctx context.Context, grpcClient *esdb.Client, option
 
 	return event, nil
 }
+
+func readEvents(ctx context.Context, grpcClient *esdb.Client, stream string, options esdb.ReadStreamOptions, count uint64) ([]*esdb.ResolvedEvent, error) {
+	read, err := grpcClient.ReadStream(ctx, stream, options, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer read.Close()
+	events := make([]*esdb.ResolvedEvent, 0, count)
+	for {
+		event, err := read.Recv()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+}
+
+func isStreamNotFound(err error) bool {
+	if esErr, ok := esdb.FromError(err); !ok {
+		return esErr.IsErrorCode(esdb.ErrorCodeResourceNotFound)
+	}
+
+	return false
+}
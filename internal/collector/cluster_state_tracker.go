@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/client"
+	log "github.com/sirupsen/logrus"
+)
+
+// clusterStateTracker remembers cluster state between scrapes, so that leader elections
+// and member state changes happening in between are not lost
+type clusterStateTracker struct {
+	mutex sync.Mutex
+
+	initialized      bool
+	lastMemberState  string
+	lastLeader       string // empty until a leader is known
+	leaderChanges    uint64
+	stateTransitions map[stateTransition]uint64
+}
+
+type stateTransition struct {
+	from string
+	to   string
+}
+
+func newClusterStateTracker() *clusterStateTracker {
+	return &clusterStateTracker{
+		stateTransitions: make(map[stateTransition]uint64),
+	}
+}
+
+func (tracker *clusterStateTracker) observe(memberState string, members []client.MemberStats) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	memberState = strings.ToLower(memberState)
+	leader, epochNumber := currentLeader(members)
+
+	if !tracker.initialized {
+		tracker.initialized = true
+		tracker.lastMemberState = memberState
+	}
+
+	if memberState != tracker.lastMemberState {
+		log.WithFields(log.Fields{
+			"from": tracker.lastMemberState,
+			"to":   memberState,
+		}).Warn("Cluster member state transition")
+
+		tracker.stateTransitions[stateTransition{from: tracker.lastMemberState, to: memberState}]++
+		tracker.lastMemberState = memberState
+	}
+
+	// during an election there may be no leader, wait until a new one is elected. The first known leader
+	// is not a change, even when the exporter started during an election.
+	if leader != "" && tracker.lastLeader == "" {
+		tracker.lastLeader = leader
+	} else if leader != "" && leader != tracker.lastLeader {
+		log.WithFields(log.Fields{
+			"previousLeader": tracker.lastLeader,
+			"leader":         leader,
+			"epochNumber":    epochNumber,
+		}).Warn("Cluster leader changed")
+
+		tracker.leaderChanges++
+		tracker.lastLeader = leader
+	}
+}
+
+func (tracker *clusterStateTracker) snapshot() (leaderChanges uint64, stateTransitions map[stateTransition]uint64) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	stateTransitions = make(map[stateTransition]uint64, len(tracker.stateTransitions))
+	for transition, count := range tracker.stateTransitions {
+		stateTransitions[transition] = count
+	}
+
+	return tracker.leaderChanges, stateTransitions
+}
+
+func currentLeader(members []client.MemberStats) (leader string, epochNumber int64) {
+	for _, member := range members {
+		if member.IsAlive && strings.EqualFold(member.State, client.MemberStateLeader) {
+			if member.InstanceID != "" {
+				return member.InstanceID, member.EpochNumber
+			}
+			return member.Name(), member.EpochNumber
+		}
+	}
+
+	return "", -1
+}
+
+// clusterEpochNumber returns the highest epoch number of alive members, ok is false when no member is alive
+func clusterEpochNumber(members []client.MemberStats) (epochNumber int64, ok bool) {
+	for _, member := range members {
+		if member.IsAlive && (!ok || member.EpochNumber > epochNumber) {
+			epochNumber, ok = member.EpochNumber, true
+		}
+	}
+
+	return epochNumber, ok
+}
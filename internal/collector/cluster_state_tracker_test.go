@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/client"
+)
+
+func Test_ClusterStateTracker_CountsLeaderChanges(t *testing.T) {
+	tracker := newClusterStateTracker()
+
+	tracker.observe("leader", []client.MemberStats{{InstanceID: "a", State: "Leader", IsAlive: true}, {InstanceID: "b", State: "Follower", IsAlive: true}})
+	tracker.observe("leader", []client.MemberStats{{InstanceID: "a", State: "Leader", IsAlive: true}, {InstanceID: "b", State: "Follower", IsAlive: true}})
+	tracker.observe("follower", []client.MemberStats{{InstanceID: "a", State: "Follower", IsAlive: true}, {InstanceID: "b", State: "Leader", IsAlive: true}})
+	tracker.observe("follower", []client.MemberStats{{InstanceID: "a", State: "Follower", IsAlive: true}, {InstanceID: "b", State: "Unknown", IsAlive: false}})
+
+	leaderChanges, stateTransitions := tracker.snapshot()
+
+	if leaderChanges != 1 {
+		t.Errorf("Expected 1 leader change, got %d", leaderChanges)
+	}
+	if len(stateTransitions) != 1 || stateTransitions[stateTransition{from: "leader", to: "follower"}] != 1 {
+		t.Errorf("Expected single leader -> follower transition, got %v", stateTransitions)
+	}
+}
+
+func Test_ClusterEpochNumber_IgnoresDeadMembers(t *testing.T) {
+	epochNumber, ok := clusterEpochNumber([]client.MemberStats{
+		{EpochNumber: 3, IsAlive: true},
+		{EpochNumber: 5, IsAlive: false},
+		{EpochNumber: 4, IsAlive: true},
+	})
+
+	if !ok || epochNumber != 4 {
+		t.Errorf("Expected epoch number 4, got %d (ok: %t)", epochNumber, ok)
+	}
+}
+
+func Test_ClusterEpochNumber_NotAvailableWithoutAliveMembers(t *testing.T) {
+	if epochNumber, ok := clusterEpochNumber([]client.MemberStats{{EpochNumber: 5, IsAlive: false}}); ok {
+		t.Errorf("Expected no epoch number, got %d", epochNumber)
+	}
+}
+
+func Test_ClusterStateTracker_FirstLeaderAfterElectionIsNotChange(t *testing.T) {
+	tracker := newClusterStateTracker()
+
+	// exporter started during an election
+	tracker.observe("follower", []client.MemberStats{{InstanceID: "a", State: "Follower", IsAlive: true}, {InstanceID: "b", State: "Follower", IsAlive: true}})
+	tracker.observe("follower", []client.MemberStats{{InstanceID: "a", State: "Follower", IsAlive: true}, {InstanceID: "b", State: "Leader", IsAlive: true}})
+
+	if leaderChanges, _ := tracker.snapshot(); leaderChanges != 0 {
+		t.Errorf("Expected no leader change, got %d", leaderChanges)
+	}
+
+	tracker.observe("follower", []client.MemberStats{{InstanceID: "a", State: "Leader", IsAlive: true}, {InstanceID: "b", State: "Follower", IsAlive: true}})
+
+	if leaderChanges, _ := tracker.snapshot(); leaderChanges != 1 {
+		t.Errorf("Expected 1 leader change, got %d", leaderChanges)
+	}
+}
@@ -2,9 +2,11 @@ package collector
 
 import (
 	"context"
-	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/marcinbudny/eventstore_exporter/internal/client"
 	"github.com/marcinbudny/eventstore_exporter/internal/config"
@@ -13,10 +15,15 @@ import (
 )
 
 type Collector struct {
-	config *config.Config
-	client *client.EventStoreStatsClient
+	currentConfig atomic.Pointer[config.Config]
+	client        *client.EventStoreStatsClient
+
+	clusterState *clusterStateTracker
+	scrapes      *sharedScrapes
 
 	up                 *prometheus.Desc
+	sharedScrapes      *prometheus.Desc
+	readQueueWait      *prometheus.Desc
 	processCPU         *prometheus.Desc
 	processMemoryBytes *prometheus.Desc
 	diskIoReadBytes    *prometheus.Desc
@@ -28,13 +35,54 @@ type Collector struct {
 	tcpReceivedBytes   *prometheus.Desc
 	tcpConnections     *prometheus.Desc
 
+	diskIoReadBytesTotal    *prometheus.Desc
+	diskIoWrittenBytesTotal *prometheus.Desc
+	diskIoReadOpsTotal      *prometheus.Desc
+	diskIoWriteOpsTotal     *prometheus.Desc
+	tcpSentBytesTotal       *prometheus.Desc
+	tcpReceivedBytesTotal   *prometheus.Desc
+
+	processStartTime            *prometheus.Desc
+	processThreads              *prometheus.Desc
+	processContentionsRate      *prometheus.Desc
+	processThrownExceptionsRate *prometheus.Desc
+
+	gcAllocationSpeed    *prometheus.Desc
+	gcFragmentationRatio *prometheus.Desc
+	gcCollections        *prometheus.Desc
+	gcGenerationSize     *prometheus.Desc
+	gcTimeRatio          *prometheus.Desc
+	gcHeapSize           *prometheus.Desc
+
 	tcpConnectionSentBytes            *prometheus.Desc
 	tcpConnectionReceivedBytes        *prometheus.Desc
 	tcpConnectionPendingSendBytes     *prometheus.Desc
 	tcpConnectionPendingReceivedBytes *prometheus.Desc
-
-	queueLength         *prometheus.Desc
-	queueItemsProcessed *prometheus.Desc
+	tcpConnectionSentBytesTotal       *prometheus.Desc
+	tcpConnectionReceivedBytesTotal   *prometheus.Desc
+
+	queueLength                   *prometheus.Desc
+	queueItemsProcessed           *prometheus.Desc
+	queueLengthCurrentTryPeak     *prometheus.Desc
+	queueLengthLifetimePeak       *prometheus.Desc
+	queueAvgItemsPerSecond        *prometheus.Desc
+	queueAvgProcessingTime        *prometheus.Desc
+	queueIdleTimeRatio            *prometheus.Desc
+	queueCurrentIdleTime          *prometheus.Desc
+	queueCurrentMessageProcessing *prometheus.Desc
+	queueInfo                     *prometheus.Desc
+
+	writerLastFlushSize       *prometheus.Desc
+	writerMeanFlushSize       *prometheus.Desc
+	writerMaxFlushSize        *prometheus.Desc
+	writerLastFlushDelay      *prometheus.Desc
+	writerMeanFlushDelay      *prometheus.Desc
+	writerMaxFlushDelay       *prometheus.Desc
+	writerQueuedFlushMessages *prometheus.Desc
+
+	readIndexCacheHits     *prometheus.Desc
+	readIndexCacheMisses   *prometheus.Desc
+	readIndexCacheHitRatio *prometheus.Desc
 
 	driveTotalBytes     *prometheus.Desc
 	driveAvailableBytes *prometheus.Desc
@@ -43,8 +91,11 @@ type Collector struct {
 	sysFreeMemoryBytes  *prometheus.Desc
 	sysTotalMemoryBytes *prometheus.Desc
 
+	projectionsEnabled                    *prometheus.Desc
 	projectionRunning                     *prometheus.Desc
 	projectionStatus                      *prometheus.Desc
+	projectionInfo                        *prometheus.Desc
+	projectionFaultReason                 *prometheus.Desc
 	projectionProgress                    *prometheus.Desc
 	projectionEventsProcessedAfterRestart *prometheus.Desc
 
@@ -53,6 +104,10 @@ type Collector struct {
 	clusterMemberIsLeader          *prometheus.Desc
 	clusterMemberIsFollower        *prometheus.Desc
 	clusterMemberIsReadonlyReplica *prometheus.Desc
+	clusterLeaderChanges           *prometheus.Desc
+	clusterEpochNumber             *prometheus.Desc
+	memberStateTransitions         *prometheus.Desc
+	clusterMemberClockSkew         *prometheus.Desc
 
 	subscriptionTotalItemsProcessed                 *prometheus.Desc
 	subscriptionLastProcessedEventNumber            *prometheus.Desc
@@ -63,17 +118,34 @@ type Collector struct {
 	subscriptionTotalInFlightMessages               *prometheus.Desc
 	subscriptionTotalNumberOfParkedMessages         *prometheus.Desc
 	subscriptionOldestParkedMessage                 *prometheus.Desc
+	subscriptionParkedMessagesSource                *prometheus.Desc
+	subscriptionParkedMessagesAge                   *prometheus.Desc
+	subscriptionParkedMessagesByEventType           *prometheus.Desc
+	subscriptionParkedMessagesByReason              *prometheus.Desc
 
 	streamLastCommitPosition *prometheus.Desc
 	streamLastEventNumber    *prometheus.Desc
+
+	scavengeRunning            *prometheus.Desc
+	scavengeLastStartTimestamp *prometheus.Desc
+	scavengeLastEndTimestamp   *prometheus.Desc
+	scavengeLastDuration       *prometheus.Desc
+	scavengeLastResult         *prometheus.Desc
+	scavengeLastSpaceSaved     *prometheus.Desc
+
+	tlsCertificateNotAfter *prometheus.Desc
 }
 
 func NewCollector(config *config.Config, client *client.EventStoreStatsClient) *Collector {
-	return &Collector{
-		config: config,
+	collector := &Collector{
 		client: client,
 
+		clusterState: newClusterStateTracker(),
+		scrapes:      &sharedScrapes{},
+
 		up:                 prometheus.NewDesc("eventstore_up", "Whether the EventStore scrape was successful", nil, nil),
+		sharedScrapes:      prometheus.NewDesc("eventstore_exporter_shared_scrapes_total", "Number of scrapes served from result of another concurrent scrape", nil, nil),
+		readQueueWait:      prometheus.NewDesc("eventstore_exporter_read_queue_wait_seconds", "Time stream and parked messages reads spent waiting for a free read slot", nil, nil),
 		processCPU:         prometheus.NewDesc("eventstore_process_cpu", "Process CPU usage, 0 - number of cores", nil, nil),
 		processMemoryBytes: prometheus.NewDesc("eventstore_process_memory_bytes", "Process memory usage, as reported by EventStore", nil, nil),
 		diskIoReadBytes:    prometheus.NewDesc("eventstore_disk_io_read_bytes", "Total number of disk IO read bytes", nil, nil),
@@ -85,13 +157,54 @@ func NewCollector(config *config.Config, client *client.EventStoreStatsClient) *
 		tcpReceivedBytes:   prometheus.NewDesc("eventstore_tcp_received_bytes", "TCP received bytes", nil, nil),
 		tcpConnections:     prometheus.NewDesc("eventstore_tcp_connections", "Current number of TCP connections", nil, nil),
 
+		diskIoReadBytesTotal:    prometheus.NewDesc("eventstore_disk_io_read_bytes_total", "Total number of disk IO read bytes", nil, nil),
+		diskIoWrittenBytesTotal: prometheus.NewDesc("eventstore_disk_io_written_bytes_total", "Total number of disk IO written bytes", nil, nil),
+		diskIoReadOpsTotal:      prometheus.NewDesc("eventstore_disk_io_read_ops_total", "Total number of disk IO read operations", nil, nil),
+		diskIoWriteOpsTotal:     prometheus.NewDesc("eventstore_disk_io_write_ops_total", "Total number of disk IO write operations", nil, nil),
+		tcpSentBytesTotal:       prometheus.NewDesc("eventstore_tcp_sent_bytes_total", "Total number of TCP sent bytes", nil, nil),
+		tcpReceivedBytesTotal:   prometheus.NewDesc("eventstore_tcp_received_bytes_total", "Total number of TCP received bytes", nil, nil),
+
+		processStartTime:            prometheus.NewDesc("eventstore_process_start_time_seconds", "Process start time since unix epoch in seconds", nil, nil),
+		processThreads:              prometheus.NewDesc("eventstore_process_threads", "Number of process threads", nil, nil),
+		processContentionsRate:      prometheus.NewDesc("eventstore_process_contentions_per_second", "Rate of lock contentions in the process", nil, nil),
+		processThrownExceptionsRate: prometheus.NewDesc("eventstore_process_thrown_exceptions_per_second", "Rate of exceptions thrown in the process", nil, nil),
+
+		gcAllocationSpeed:    prometheus.NewDesc("eventstore_process_gc_allocation_speed_bytes_per_second", "Rate of memory allocation in bytes per second", nil, nil),
+		gcFragmentationRatio: prometheus.NewDesc("eventstore_process_gc_fragmentation_ratio", "GC heap fragmentation 0 - 1", nil, nil),
+		gcCollections:        prometheus.NewDesc("eventstore_process_gc_collections_total", "Number of garbage collections of specified generation", []string{"generation"}, nil),
+		gcGenerationSize:     prometheus.NewDesc("eventstore_process_gc_generation_size_bytes", "Size of specified GC generation in bytes, loh = large object heap", []string{"generation"}, nil),
+		gcTimeRatio:          prometheus.NewDesc("eventstore_process_gc_time_ratio", "Time spent in GC 0 - 1", nil, nil),
+		gcHeapSize:           prometheus.NewDesc("eventstore_process_gc_heap_size_bytes", "Total size of GC heaps in bytes", nil, nil),
+
 		tcpConnectionSentBytes:            prometheus.NewDesc("eventstore_tcp_connection_sent_bytes", "TCP connection total sent bytes", []string{"id", "client_name", "remote_endpoint", "local_endpoint", "external", "ssl"}, nil),
 		tcpConnectionReceivedBytes:        prometheus.NewDesc("eventstore_tcp_connection_received_bytes", "TCP connection total received bytes", []string{"id", "client_name", "remote_endpoint", "local_endpoint", "external", "ssl"}, nil),
 		tcpConnectionPendingSendBytes:     prometheus.NewDesc("eventstore_tcp_connection_pending_send_bytes", "TCP connection pending send bytes", []string{"id", "client_name", "remote_endpoint", "local_endpoint", "external", "ssl"}, nil),
 		tcpConnectionPendingReceivedBytes: prometheus.NewDesc("eventstore_tcp_connection_pending_received_bytes", "TCP connection pending received bytes", []string{"id", "client_name", "remote_endpoint", "local_endpoint", "external", "ssl"}, nil),
-
-		queueLength:         prometheus.NewDesc("eventstore_queue_length", "Queue length", []string{"queue"}, nil),
-		queueItemsProcessed: prometheus.NewDesc("eventstore_queue_items_processed_total", "Total number items processed by queue", []string{"queue"}, nil),
+		tcpConnectionSentBytesTotal:       prometheus.NewDesc("eventstore_tcp_connection_sent_bytes_total", "TCP connection total sent bytes", []string{"id", "client_name", "remote_endpoint", "local_endpoint", "external", "ssl"}, nil),
+		tcpConnectionReceivedBytesTotal:   prometheus.NewDesc("eventstore_tcp_connection_received_bytes_total", "TCP connection total received bytes", []string{"id", "client_name", "remote_endpoint", "local_endpoint", "external", "ssl"}, nil),
+
+		queueLength:                   prometheus.NewDesc("eventstore_queue_length", "Queue length", []string{"queue"}, nil),
+		queueItemsProcessed:           prometheus.NewDesc("eventstore_queue_items_processed_total", "Total number items processed by queue", []string{"queue"}, nil),
+		queueLengthCurrentTryPeak:     prometheus.NewDesc("eventstore_queue_length_current_try_peak", "Queue length peak in current measurement period", []string{"queue", "queue_group"}, nil),
+		queueLengthLifetimePeak:       prometheus.NewDesc("eventstore_queue_length_lifetime_peak", "Queue length peak since process start", []string{"queue", "queue_group"}, nil),
+		queueAvgItemsPerSecond:        prometheus.NewDesc("eventstore_queue_avg_items_per_second", "Average number of items processed by queue per second", []string{"queue", "queue_group"}, nil),
+		queueAvgProcessingTime:        prometheus.NewDesc("eventstore_queue_avg_processing_time_seconds", "Average item processing time in seconds", []string{"queue", "queue_group"}, nil),
+		queueIdleTimeRatio:            prometheus.NewDesc("eventstore_queue_idle_time_ratio", "Queue idle time 0 - 1, where 1 = queue idle 100% of time", []string{"queue", "queue_group"}, nil),
+		queueCurrentIdleTime:          prometheus.NewDesc("eventstore_queue_current_idle_seconds", "Time the queue has been idle for, 0 if currently processing a message", []string{"queue", "queue_group"}, nil),
+		queueCurrentMessageProcessing: prometheus.NewDesc("eventstore_queue_current_message_processing_seconds", "Time the queue has been processing current message for, 0 if idle", []string{"queue", "queue_group"}, nil),
+		queueInfo:                     prometheus.NewDesc("eventstore_queue_info", "Queue information, value is always 1", []string{"queue", "queue_group", "in_progress_message", "last_processed_message"}, nil),
+
+		writerLastFlushSize:       prometheus.NewDesc("eventstore_writer_last_flush_size_bytes", "Size of the last writer flush in bytes", nil, nil),
+		writerMeanFlushSize:       prometheus.NewDesc("eventstore_writer_mean_flush_size_bytes", "Mean size of writer flushes in bytes", nil, nil),
+		writerMaxFlushSize:        prometheus.NewDesc("eventstore_writer_max_flush_size_bytes", "Max size of writer flushes in bytes", nil, nil),
+		writerLastFlushDelay:      prometheus.NewDesc("eventstore_writer_last_flush_delay_seconds", "Duration of the last writer flush in seconds", nil, nil),
+		writerMeanFlushDelay:      prometheus.NewDesc("eventstore_writer_mean_flush_delay_seconds", "Mean duration of writer flushes in seconds", nil, nil),
+		writerMaxFlushDelay:       prometheus.NewDesc("eventstore_writer_max_flush_delay_seconds", "Max duration of writer flushes in seconds", nil, nil),
+		writerQueuedFlushMessages: prometheus.NewDesc("eventstore_writer_queued_flush_messages", "Number of messages queued for flush by writer", nil, nil),
+
+		readIndexCacheHits:     prometheus.NewDesc("eventstore_read_index_cache_hits_total", "Total number of read index cache hits", []string{"cache"}, nil),
+		readIndexCacheMisses:   prometheus.NewDesc("eventstore_read_index_cache_misses_total", "Total number of read index cache misses", []string{"cache"}, nil),
+		readIndexCacheHitRatio: prometheus.NewDesc("eventstore_read_index_cache_hit_ratio", "Read index cache hit ratio 0 - 1 since process start", []string{"cache"}, nil),
 
 		driveTotalBytes:     prometheus.NewDesc("eventstore_drive_total_bytes", "Drive total size in bytes", []string{"drive"}, nil),
 		driveAvailableBytes: prometheus.NewDesc("eventstore_drive_available_bytes", "Drive available bytes", []string{"drive"}, nil),
@@ -100,8 +213,11 @@ func NewCollector(config *config.Config, client *client.EventStoreStatsClient) *
 		sysFreeMemoryBytes:  prometheus.NewDesc("eventstore_sys_free_memory_bytes", "System free memory in bytes", nil, nil),
 		sysTotalMemoryBytes: prometheus.NewDesc("eventstore_sys_total_memory_bytes", "System total memory in bytes", nil, nil),
 
+		projectionsEnabled:                    prometheus.NewDesc("eventstore_projections_enabled", "If 1, projections subsystem is running and projection stats are available", nil, nil),
 		projectionRunning:                     prometheus.NewDesc("eventstore_projection_running", "If 1, projection is in 'Running' state", []string{"projection"}, nil),
 		projectionStatus:                      prometheus.NewDesc("eventstore_projection_status", "If 1, projection is in specified state", []string{"projection", "status"}, nil),
+		projectionInfo:                        prometheus.NewDesc("eventstore_projection_info", "Projection mode, whether it is enabled (unknown when read over gRPC) and checkpoint status", []string{"projection", "mode", "enabled", "checkpoint_status"}, nil),
+		projectionFaultReason:                 prometheus.NewDesc("eventstore_projection_fault_reason_info", "Reason of projection fault, truncated", []string{"projection", "reason"}, nil),
 		projectionProgress:                    prometheus.NewDesc("eventstore_projection_progress", "Projection progress 0 - 1, where 1 = projection progress at 100%", []string{"projection"}, nil),
 		projectionEventsProcessedAfterRestart: prometheus.NewDesc("eventstore_projection_events_processed_after_restart_total", "Projection event processed count after restart", []string{"projection"}, nil),
 
@@ -110,6 +226,10 @@ func NewCollector(config *config.Config, client *client.EventStoreStatsClient) *
 		clusterMemberIsLeader:          prometheus.NewDesc("eventstore_cluster_member_is_leader", "If 1, current cluster member is the leader", nil, nil),
 		clusterMemberIsFollower:        prometheus.NewDesc("eventstore_cluster_member_is_follower", "If 1, current cluster member is a follower", nil, nil),
 		clusterMemberIsReadonlyReplica: prometheus.NewDesc("eventstore_cluster_member_is_readonly_replica", "If 1, current cluster member is a readonly replica", nil, nil),
+		clusterLeaderChanges:           prometheus.NewDesc("eventstore_cluster_leader_changes_total", "Number of leader changes observed by the exporter", nil, nil),
+		clusterEpochNumber:             prometheus.NewDesc("eventstore_cluster_epoch_number", "Highest epoch number reported by alive cluster members, not exported when no member is alive", nil, nil),
+		memberStateTransitions:         prometheus.NewDesc("eventstore_member_state_transitions_total", "Number of state transitions of current cluster member observed by the exporter", []string{"from", "to"}, nil),
+		clusterMemberClockSkew:         prometheus.NewDesc("eventstore_cluster_member_clock_skew_seconds", "Difference between gossip timestamp of cluster member and the reference clock (exporter or leader), 0 if within tolerance", []string{"member", "reference"}, nil),
 
 		subscriptionTotalItemsProcessed:                 prometheus.NewDesc("eventstore_subscription_items_processed_total", "Total items processed by subscription", []string{"event_stream_id", "group_name"}, nil),
 		subscriptionLastProcessedEventNumber:            prometheus.NewDesc("eventstore_subscription_last_processed_event_number", "Last event number processed by subscription (streams other than $all)", []string{"event_stream_id", "group_name"}, nil),
@@ -120,34 +240,102 @@ func NewCollector(config *config.Config, client *client.EventStoreStatsClient) *
 		subscriptionTotalInFlightMessages:               prometheus.NewDesc("eventstore_subscription_messages_in_flight", "Number of messages in flight for subscription", []string{"event_stream_id", "group_name"}, nil),
 		subscriptionTotalNumberOfParkedMessages:         prometheus.NewDesc("eventstore_subscription_parked_messages", "Number of parked messages for subscription", []string{"event_stream_id", "group_name"}, nil),
 		subscriptionOldestParkedMessage:                 prometheus.NewDesc("eventstore_subscription_oldest_parked_message_age_seconds", "Oldest parked message age for subscription in seconds", []string{"event_stream_id", "group_name"}, nil),
+		subscriptionParkedMessagesSource:                prometheus.NewDesc("eventstore_subscription_parked_messages_source", "Source of parked messages stats for subscription, subscription_info or stream", []string{"event_stream_id", "group_name", "source"}, nil),
+		subscriptionParkedMessagesAge:                   prometheus.NewDesc("eventstore_subscription_parked_messages_age_seconds", "Age of analyzed parked messages for subscription", []string{"event_stream_id", "group_name"}, nil),
+		subscriptionParkedMessagesByEventType:           prometheus.NewDesc("eventstore_subscription_parked_messages_by_event_type", "Number of analyzed parked messages for subscription per original event type", []string{"event_stream_id", "group_name", "event_type"}, nil),
+		subscriptionParkedMessagesByReason:              prometheus.NewDesc("eventstore_subscription_parked_messages_by_reason", "Number of analyzed parked messages for subscription per park reason", []string{"event_stream_id", "group_name", "reason"}, nil),
 
 		streamLastEventNumber:    prometheus.NewDesc("eventstore_stream_last_event_number", "Last event number in a stream (streams other than $all)", []string{"event_stream_id"}, nil),
 		streamLastCommitPosition: prometheus.NewDesc("eventstore_stream_last_commit_position", "Last commit position in a stream ($all stream only)", []string{"event_stream_id"}, nil),
+
+		scavengeRunning:            prometheus.NewDesc("eventstore_scavenge_running", "If 1, scavenge is currently running on the node", []string{"node"}, nil),
+		scavengeLastStartTimestamp: prometheus.NewDesc("eventstore_scavenge_last_start_timestamp_seconds", "Start time of the last scavenge since unix epoch in seconds", []string{"node"}, nil),
+		scavengeLastEndTimestamp:   prometheus.NewDesc("eventstore_scavenge_last_end_timestamp_seconds", "End time of the last completed scavenge since unix epoch in seconds", []string{"node"}, nil),
+		scavengeLastDuration:       prometheus.NewDesc("eventstore_scavenge_last_duration_seconds", "Duration of the last scavenge in seconds, or time elapsed since start if still running", []string{"node"}, nil),
+		scavengeLastResult:         prometheus.NewDesc("eventstore_scavenge_last_result", "Result of the last completed scavenge, value is always 1", []string{"node", "result"}, nil),
+		scavengeLastSpaceSaved:     prometheus.NewDesc("eventstore_scavenge_last_space_saved_bytes", "Space saved by the last completed scavenge in bytes", []string{"node"}, nil),
+
+		tlsCertificateNotAfter: prometheus.NewDesc("eventstore_tls_certificate_not_after_seconds", "Expiry time of TLS certificate presented to the exporter by cluster member since unix epoch in seconds", []string{"member", "subject", "issuer", "serial"}, nil),
 	}
+	collector.currentConfig.Store(config)
+
+	return collector
+}
+
+// UpdateConfig swaps config used by the collector, state tracked between scrapes is preserved
+func (c *Collector) UpdateConfig(newConfig *config.Config) {
+	c.currentConfig.Store(newConfig)
+}
+
+func (c *Collector) config() *config.Config {
+	return c.currentConfig.Load()
 }
 
+// Describe sends all descriptors regardless of config, since enabled stats can change when config is reloaded
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.up
+	ch <- c.sharedScrapes
+	ch <- c.readQueueWait
 	ch <- c.processCPU
 	ch <- c.processMemoryBytes
+	ch <- c.uptimeSeconds
+	ch <- c.tcpConnections
+
 	ch <- c.diskIoReadBytes
 	ch <- c.diskIoWrittenBytes
 	ch <- c.diskIoReadOps
 	ch <- c.diskIoWriteOps
-	ch <- c.uptimeSeconds
 	ch <- c.tcpSentBytes
 	ch <- c.tcpReceivedBytes
-	ch <- c.tcpConnections
 
-	if c.config.EnableTCPConnectionStats {
-		ch <- c.tcpConnectionSentBytes
-		ch <- c.tcpConnectionReceivedBytes
-		ch <- c.tcpConnectionPendingSendBytes
-		ch <- c.tcpConnectionPendingReceivedBytes
-	}
+	ch <- c.diskIoReadBytesTotal
+	ch <- c.diskIoWrittenBytesTotal
+	ch <- c.diskIoReadOpsTotal
+	ch <- c.diskIoWriteOpsTotal
+	ch <- c.tcpSentBytesTotal
+	ch <- c.tcpReceivedBytesTotal
+
+	ch <- c.processStartTime
+	ch <- c.processThreads
+	ch <- c.processContentionsRate
+	ch <- c.processThrownExceptionsRate
+
+	ch <- c.gcAllocationSpeed
+	ch <- c.gcFragmentationRatio
+	ch <- c.gcCollections
+	ch <- c.gcGenerationSize
+	ch <- c.gcTimeRatio
+	ch <- c.gcHeapSize
+
+	ch <- c.tcpConnectionSentBytes
+	ch <- c.tcpConnectionReceivedBytes
+	ch <- c.tcpConnectionSentBytesTotal
+	ch <- c.tcpConnectionReceivedBytesTotal
+	ch <- c.tcpConnectionPendingSendBytes
+	ch <- c.tcpConnectionPendingReceivedBytes
 
 	ch <- c.queueLength
 	ch <- c.queueItemsProcessed
+	ch <- c.queueLengthCurrentTryPeak
+	ch <- c.queueLengthLifetimePeak
+	ch <- c.queueAvgItemsPerSecond
+	ch <- c.queueAvgProcessingTime
+	ch <- c.queueIdleTimeRatio
+	ch <- c.queueCurrentIdleTime
+	ch <- c.queueCurrentMessageProcessing
+	ch <- c.queueInfo
+
+	ch <- c.writerLastFlushSize
+	ch <- c.writerMeanFlushSize
+	ch <- c.writerMaxFlushSize
+	ch <- c.writerLastFlushDelay
+	ch <- c.writerMeanFlushDelay
+	ch <- c.writerMaxFlushDelay
+	ch <- c.writerQueuedFlushMessages
+
+	ch <- c.readIndexCacheHits
+	ch <- c.readIndexCacheMisses
+	ch <- c.readIndexCacheHitRatio
 
 	ch <- c.driveTotalBytes
 	ch <- c.driveAvailableBytes
@@ -156,8 +344,11 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.sysFreeMemoryBytes
 	ch <- c.sysTotalMemoryBytes
 
+	ch <- c.projectionsEnabled
 	ch <- c.projectionRunning
 	ch <- c.projectionStatus
+	ch <- c.projectionInfo
+	ch <- c.projectionFaultReason
 	ch <- c.projectionProgress
 	ch <- c.projectionEventsProcessedAfterRestart
 
@@ -166,6 +357,10 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.clusterMemberIsLeader
 	ch <- c.clusterMemberIsFollower
 	ch <- c.clusterMemberIsReadonlyReplica
+	ch <- c.clusterLeaderChanges
+	ch <- c.clusterEpochNumber
+	ch <- c.memberStateTransitions
+	ch <- c.clusterMemberClockSkew
 
 	ch <- c.subscriptionTotalItemsProcessed
 	ch <- c.subscriptionLastProcessedEventNumber
@@ -176,17 +371,50 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.subscriptionTotalInFlightMessages
 	ch <- c.subscriptionTotalNumberOfParkedMessages
 	ch <- c.subscriptionOldestParkedMessage
+	ch <- c.subscriptionParkedMessagesSource
+	ch <- c.subscriptionParkedMessagesAge
+	ch <- c.subscriptionParkedMessagesByEventType
+	ch <- c.subscriptionParkedMessagesByReason
+
+	ch <- c.scavengeRunning
+	ch <- c.scavengeLastStartTimestamp
+	ch <- c.scavengeLastEndTimestamp
+	ch <- c.scavengeLastDuration
+	ch <- c.scavengeLastResult
+	ch <- c.scavengeLastSpaceSaved
+
+	ch <- c.tlsCertificateNotAfter
 }
 
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(context.Background(), ch)
+}
+
+// WithContext returns collector that gets stats within given context, e.g. one bound to the scrape request
+func (c *Collector) WithContext(ctx context.Context) prometheus.Collector {
+	return &contextCollector{Collector: c, ctx: ctx}
+}
+
+type contextCollector struct {
+	*Collector
+	ctx context.Context // nolint: containedctx
+}
+
+func (c *contextCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(c.ctx, ch)
+}
+
+func (c *Collector) collect(ctx context.Context, ch chan<- prometheus.Metric) {
 	log.Info("Running scrape")
 
-	// context is not passed to the collector, so we need to create a new one
-	// https://groups.google.com/g/prometheus-developers/c/a8k4CXhGdPI
-	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	// configured timeout applies even when the context has a later deadline
+	ctx, cancel := context.WithTimeout(ctx, c.config().Timeout)
 	defer cancel()
 
-	if stats, err := c.client.GetStats(ctx); err != nil {
+	stats, err := c.scrapes.getStats(ctx, scrapeKey(c.config()), c.config().Timeout, c.client.GetStats)
+	ch <- prometheus.MustNewConstMetric(c.sharedScrapes, prometheus.CounterValue, float64(c.scrapes.sharedCount()))
+
+	if err != nil {
 		log.WithError(err).Error("Error while getting data from EventStore")
 
 		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
@@ -199,26 +427,94 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 
 func (c *Collector) collectFromStats(ch chan<- prometheus.Metric, stats *client.Stats) {
 	c.collectFromServerStats(ch, stats)
+	c.collectFromRuntimeStats(ch, stats.Server.Process)
 	c.collectFromTCPConnectionStats(ch, stats.TCPConnections)
 	c.collectFromQueueStats(ch, stats.Server.Es.Queues)
+	c.collectFromWriterStats(ch, stats.Server.Es.Writer)
+	c.collectFromReadIndexStats(ch, stats.Server.Es.ReadIndex)
 	c.collectFromDriveStats(ch, stats.Server.System.Drives)
 	c.collectFromSystemStats(ch, stats.Server.System)
-	c.collectFromProjectionStats(ch, stats.Projections)
+	c.collectFromProjectionStats(ch, stats.ProjectionsEnabled, stats.Projections)
+	c.collectFromProjectionMetrics(ch, stats.ProjectionMetrics)
 	c.collectFromSubscriptionStats(ch, stats.Subscriptions)
 	c.collectFromStreamStats(ch, stats.Streams)
+	c.collectFromStreamMetrics(ch, stats.StreamMetrics)
 	c.collectFromClusterStats(ch, stats)
+	c.collectFromScavengeStats(ch, stats.Scavenges)
+	c.collectFromTLSCertificateStats(ch, stats.Certificates)
+
+	ch <- prometheus.MustNewConstHistogram(c.readQueueWait, stats.ReadQueueWait.Count, stats.ReadQueueWait.Sum, stats.ReadQueueWait.Buckets)
 }
 
 func (c *Collector) collectFromServerStats(ch chan<- prometheus.Metric, stats *client.Stats) {
-	ch <- prometheus.MustNewConstMetric(c.processCPU, prometheus.GaugeValue, stats.Server.Process.CPU/100.0) // scale to 0-[num of cores]
-	ch <- prometheus.MustNewConstMetric(c.processMemoryBytes, prometheus.GaugeValue, float64(stats.Server.Process.MemoryBytes))
-	ch <- prometheus.MustNewConstMetric(c.diskIoReadBytes, prometheus.GaugeValue, float64(stats.Server.Process.DiskIo.ReadBytes))
-	ch <- prometheus.MustNewConstMetric(c.diskIoWrittenBytes, prometheus.GaugeValue, float64(stats.Server.Process.DiskIo.WrittenBytes))
-	ch <- prometheus.MustNewConstMetric(c.diskIoReadOps, prometheus.GaugeValue, float64(stats.Server.Process.DiskIo.ReadOps))
-	ch <- prometheus.MustNewConstMetric(c.diskIoWriteOps, prometheus.GaugeValue, float64(stats.Server.Process.DiskIo.WriteOps))
-	ch <- prometheus.MustNewConstMetric(c.tcpSentBytes, prometheus.GaugeValue, float64(stats.Server.Process.TCP.SentBytes))
-	ch <- prometheus.MustNewConstMetric(c.tcpReceivedBytes, prometheus.GaugeValue, float64(stats.Server.Process.TCP.ReceivedBytes))
-	ch <- prometheus.MustNewConstMetric(c.tcpConnections, prometheus.GaugeValue, float64(stats.Server.Process.TCP.Connections))
+	process := stats.Server.Process
+
+	ch <- prometheus.MustNewConstMetric(c.processCPU, prometheus.GaugeValue, process.CPU/100.0) // scale to 0-[num of cores]
+	ch <- prometheus.MustNewConstMetric(c.processMemoryBytes, prometheus.GaugeValue, float64(process.MemoryBytes))
+	ch <- prometheus.MustNewConstMetric(c.tcpConnections, prometheus.GaugeValue, float64(process.TCP.Connections))
+
+	if !process.StartTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.uptimeSeconds, prometheus.GaugeValue, time.Since(process.StartTime.Time).Seconds())
+	}
+
+	if c.emitsLegacyGauges() {
+		ch <- prometheus.MustNewConstMetric(c.diskIoReadBytes, prometheus.GaugeValue, float64(process.DiskIo.ReadBytes))
+		ch <- prometheus.MustNewConstMetric(c.diskIoWrittenBytes, prometheus.GaugeValue, float64(process.DiskIo.WrittenBytes))
+		ch <- prometheus.MustNewConstMetric(c.diskIoReadOps, prometheus.GaugeValue, float64(process.DiskIo.ReadOps))
+		ch <- prometheus.MustNewConstMetric(c.diskIoWriteOps, prometheus.GaugeValue, float64(process.DiskIo.WriteOps))
+		ch <- prometheus.MustNewConstMetric(c.tcpSentBytes, prometheus.GaugeValue, float64(process.TCP.SentBytes))
+		ch <- prometheus.MustNewConstMetric(c.tcpReceivedBytes, prometheus.GaugeValue, float64(process.TCP.ReceivedBytes))
+	}
+
+	if c.emitsCounters() {
+		ch <- processCounter(c.diskIoReadBytesTotal, float64(process.DiskIo.ReadBytes), process.StartTime)
+		ch <- processCounter(c.diskIoWrittenBytesTotal, float64(process.DiskIo.WrittenBytes), process.StartTime)
+		ch <- processCounter(c.diskIoReadOpsTotal, float64(process.DiskIo.ReadOps), process.StartTime)
+		ch <- processCounter(c.diskIoWriteOpsTotal, float64(process.DiskIo.WriteOps), process.StartTime)
+		ch <- processCounter(c.tcpSentBytesTotal, float64(process.TCP.SentBytes), process.StartTime)
+		ch <- processCounter(c.tcpReceivedBytesTotal, float64(process.TCP.ReceivedBytes), process.StartTime)
+	}
+}
+
+// processCounter creates a counter of a value that is reset on EventStore process restart. Process start time
+// is exposed as the counter's created timestamp, so that the reset can be detected even if the value grows
+// past the previous one between scrapes. Created timestamp is only sent in protobuf format and Prometheus
+// uses it only with created-timestamp-zero-ingestion feature enabled.
+func processCounter(desc *prometheus.Desc, value float64, startTime client.DotNetDateTime, labels ...string) prometheus.Metric {
+	if startTime.IsZero() {
+		return prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labels...)
+	}
+	return prometheus.MustNewConstMetricWithCreatedTimestamp(desc, prometheus.CounterValue, value, startTime.Time, labels...)
+}
+
+func (c *Collector) emitsLegacyGauges() bool {
+	return c.config().MetricsVersion < 2 || c.config().EnableLegacyGaugeMetrics
+}
+
+func (c *Collector) emitsCounters() bool {
+	return c.config().MetricsVersion >= 2
+}
+
+func (c *Collector) collectFromRuntimeStats(ch chan<- prometheus.Metric, stats client.ProcessStats) {
+	if !stats.StartTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.processStartTime, prometheus.GaugeValue, float64(stats.StartTime.UnixMilli())/1000.0)
+	}
+	ch <- prometheus.MustNewConstMetric(c.processThreads, prometheus.GaugeValue, float64(stats.ThreadsCount))
+	ch <- prometheus.MustNewConstMetric(c.processContentionsRate, prometheus.GaugeValue, stats.ContentionsRate)
+	ch <- prometheus.MustNewConstMetric(c.processThrownExceptionsRate, prometheus.GaugeValue, stats.ThrownExceptionsRate)
+
+	gc := stats.GC
+	ch <- prometheus.MustNewConstMetric(c.gcAllocationSpeed, prometheus.GaugeValue, gc.AllocationSpeed)
+	ch <- prometheus.MustNewConstMetric(c.gcFragmentationRatio, prometheus.GaugeValue, gc.FragmentationPct/100.0) // scale to 0-1
+	ch <- prometheus.MustNewConstMetric(c.gcCollections, prometheus.CounterValue, float64(gc.Gen0ItemsCount), "0")
+	ch <- prometheus.MustNewConstMetric(c.gcCollections, prometheus.CounterValue, float64(gc.Gen1ItemsCount), "1")
+	ch <- prometheus.MustNewConstMetric(c.gcCollections, prometheus.CounterValue, float64(gc.Gen2ItemsCount), "2")
+	ch <- prometheus.MustNewConstMetric(c.gcGenerationSize, prometheus.GaugeValue, float64(gc.Gen0Size), "0")
+	ch <- prometheus.MustNewConstMetric(c.gcGenerationSize, prometheus.GaugeValue, float64(gc.Gen1Size), "1")
+	ch <- prometheus.MustNewConstMetric(c.gcGenerationSize, prometheus.GaugeValue, float64(gc.Gen2Size), "2")
+	ch <- prometheus.MustNewConstMetric(c.gcGenerationSize, prometheus.GaugeValue, float64(gc.LargeHeapSize), "loh")
+	ch <- prometheus.MustNewConstMetric(c.gcTimeRatio, prometheus.GaugeValue, gc.TimeInGcPct/100.0) // scale to 0-1
+	ch <- prometheus.MustNewConstMetric(c.gcHeapSize, prometheus.GaugeValue, float64(gc.TotalBytesInHeaps))
 }
 
 func (c *Collector) collectFromTCPConnectionStats(ch chan<- prometheus.Metric, stats []client.TCPConnectionStats) {
@@ -232,8 +528,14 @@ func (c *Collector) collectFromTCPConnectionStats(ch chan<- prometheus.Metric, s
 
 		labels := []string{id, clientName, remoteEndPoint, localEndPoint, external, ssl}
 
-		ch <- prometheus.MustNewConstMetric(c.tcpConnectionSentBytes, prometheus.CounterValue, float64(tcpConn.TotalBytesSent), labels...)
-		ch <- prometheus.MustNewConstMetric(c.tcpConnectionReceivedBytes, prometheus.CounterValue, float64(tcpConn.TotalBytesReceived), labels...)
+		if c.emitsLegacyGauges() {
+			ch <- prometheus.MustNewConstMetric(c.tcpConnectionSentBytes, prometheus.CounterValue, float64(tcpConn.TotalBytesSent), labels...)
+			ch <- prometheus.MustNewConstMetric(c.tcpConnectionReceivedBytes, prometheus.CounterValue, float64(tcpConn.TotalBytesReceived), labels...)
+		}
+		if c.emitsCounters() {
+			ch <- prometheus.MustNewConstMetric(c.tcpConnectionSentBytesTotal, prometheus.CounterValue, float64(tcpConn.TotalBytesSent), labels...)
+			ch <- prometheus.MustNewConstMetric(c.tcpConnectionReceivedBytesTotal, prometheus.CounterValue, float64(tcpConn.TotalBytesReceived), labels...)
+		}
 		ch <- prometheus.MustNewConstMetric(c.tcpConnectionPendingSendBytes, prometheus.GaugeValue, float64(tcpConn.PendingSendBytes), labels...)
 		ch <- prometheus.MustNewConstMetric(c.tcpConnectionPendingReceivedBytes, prometheus.GaugeValue, float64(tcpConn.PendingReceivedBytes), labels...)
 	}
@@ -243,6 +545,42 @@ func (c *Collector) collectFromQueueStats(ch chan<- prometheus.Metric, stats map
 	for _, queue := range stats {
 		ch <- prometheus.MustNewConstMetric(c.queueLength, prometheus.GaugeValue, float64(queue.Length), queue.QueueName)
 		ch <- prometheus.MustNewConstMetric(c.queueItemsProcessed, prometheus.CounterValue, float64(queue.ItemsProcessed), queue.QueueName)
+
+		labels := []string{queue.QueueName, queue.GroupName}
+
+		ch <- prometheus.MustNewConstMetric(c.queueLengthCurrentTryPeak, prometheus.GaugeValue, float64(queue.LengthCurrentTryPeak), labels...)
+		ch <- prometheus.MustNewConstMetric(c.queueLengthLifetimePeak, prometheus.GaugeValue, float64(queue.LengthLifetimePeak), labels...)
+		ch <- prometheus.MustNewConstMetric(c.queueAvgItemsPerSecond, prometheus.GaugeValue, queue.AvgItemsPerSecond, labels...)
+		ch <- prometheus.MustNewConstMetric(c.queueAvgProcessingTime, prometheus.GaugeValue, queue.AvgProcessingTimeMs/1000.0, labels...) // scale to seconds
+		ch <- prometheus.MustNewConstMetric(c.queueIdleTimeRatio, prometheus.GaugeValue, queue.IdleTimePercent/100.0, labels...)          // scale to 0-1
+		ch <- prometheus.MustNewConstMetric(c.queueCurrentIdleTime, prometheus.GaugeValue, queue.CurrentIdleTime.Seconds(), labels...)
+		ch <- prometheus.MustNewConstMetric(c.queueCurrentMessageProcessing, prometheus.GaugeValue, queue.CurrentItemProcessingTime.Seconds(), labels...)
+		ch <- prometheus.MustNewConstMetric(c.queueInfo, prometheus.GaugeValue, 1, queue.QueueName, queue.GroupName, queue.InProgressMessage, queue.LastProcessedMessage)
+	}
+}
+
+func (c *Collector) collectFromWriterStats(ch chan<- prometheus.Metric, stats client.WriterStats) {
+	ch <- prometheus.MustNewConstMetric(c.writerLastFlushSize, prometheus.GaugeValue, float64(stats.LastFlushSize))
+	ch <- prometheus.MustNewConstMetric(c.writerMeanFlushSize, prometheus.GaugeValue, stats.MeanFlushSize)
+	ch <- prometheus.MustNewConstMetric(c.writerMaxFlushSize, prometheus.GaugeValue, float64(stats.MaxFlushSize))
+	ch <- prometheus.MustNewConstMetric(c.writerLastFlushDelay, prometheus.GaugeValue, stats.LastFlushDelayMs/1000.0) // scale to seconds
+	ch <- prometheus.MustNewConstMetric(c.writerMeanFlushDelay, prometheus.GaugeValue, stats.MeanFlushDelayMs/1000.0)
+	ch <- prometheus.MustNewConstMetric(c.writerMaxFlushDelay, prometheus.GaugeValue, stats.MaxFlushDelayMs/1000.0)
+	ch <- prometheus.MustNewConstMetric(c.writerQueuedFlushMessages, prometheus.GaugeValue, float64(stats.QueuedFlushMessages))
+}
+
+func (c *Collector) collectFromReadIndexStats(ch chan<- prometheus.Metric, stats client.ReadIndexStats) {
+	c.collectReadIndexCacheStats(ch, "record", stats.CachedRecord, stats.NotCachedRecord)
+	c.collectReadIndexCacheStats(ch, "stream_info", stats.CachedStreamInfo, stats.NotCachedStreamInfo)
+	c.collectReadIndexCacheStats(ch, "transaction_info", stats.CachedTransInfo, stats.NotCachedTransInfo)
+}
+
+func (c *Collector) collectReadIndexCacheStats(ch chan<- prometheus.Metric, cache string, hits int64, misses int64) {
+	ch <- prometheus.MustNewConstMetric(c.readIndexCacheHits, prometheus.CounterValue, float64(hits), cache)
+	ch <- prometheus.MustNewConstMetric(c.readIndexCacheMisses, prometheus.CounterValue, float64(misses), cache)
+
+	if hits+misses > 0 {
+		ch <- prometheus.MustNewConstMetric(c.readIndexCacheHitRatio, prometheus.GaugeValue, float64(hits)/float64(hits+misses), cache)
 	}
 }
 
@@ -262,29 +600,65 @@ func (c *Collector) collectFromSystemStats(ch chan<- prometheus.Metric, stats cl
 	ch <- prometheus.MustNewConstMetric(c.sysTotalMemoryBytes, prometheus.GaugeValue, float64(stats.TotalMem))
 }
 
-func (c *Collector) collectFromProjectionStats(ch chan<- prometheus.Metric, stats []client.ProjectionStats) {
+var alwaysReportedProjectionStates = []client.ProjectionState{client.ProjectionStateRunning, client.ProjectionStateStopped, client.ProjectionStateFaulted}
+
+func (c *Collector) collectFromProjectionStats(ch chan<- prometheus.Metric, enabled bool, stats []client.ProjectionStats) {
+	projectionsEnabled := 0.0
+	if enabled {
+		projectionsEnabled = 1.0
+	}
+	ch <- prometheus.MustNewConstMetric(c.projectionsEnabled, prometheus.GaugeValue, projectionsEnabled)
+
 	for _, projection := range stats {
-		running := 0.0
-		stopped := 0.0
-		faulted := 0.0
+		state := projection.State()
 
-		if projection.Status == "Running" {
+		running := 0.0
+		if state == client.ProjectionStateRunning {
 			running = 1.0
-		} else if projection.Status == "Stopped" {
-			stopped = 1.0
-		} else if strings.Contains(projection.Status, "Faulted") {
-			faulted = 1.0
 		}
-
 		ch <- prometheus.MustNewConstMetric(c.projectionRunning, prometheus.GaugeValue, running, projection.EffectiveName)
-		ch <- prometheus.MustNewConstMetric(c.projectionStatus, prometheus.GaugeValue, running, projection.EffectiveName, "Running")
-		ch <- prometheus.MustNewConstMetric(c.projectionStatus, prometheus.GaugeValue, stopped, projection.EffectiveName, "Stopped")
-		ch <- prometheus.MustNewConstMetric(c.projectionStatus, prometheus.GaugeValue, faulted, projection.EffectiveName, "Faulted")
+
+		// states reported before status parsing are always exported, so that existing queries comparing them
+		// with 0 keep working, other states are only exported when projection is in them
+		for _, reportedState := range alwaysReportedProjectionStates {
+			inState := 0.0
+			if state == reportedState {
+				inState = 1.0
+			}
+			ch <- prometheus.MustNewConstMetric(c.projectionStatus, prometheus.GaugeValue, inState, projection.EffectiveName, string(reportedState))
+		}
+		if !slices.Contains(alwaysReportedProjectionStates, state) {
+			ch <- prometheus.MustNewConstMetric(c.projectionStatus, prometheus.GaugeValue, 1, projection.EffectiveName, string(state))
+		}
+
+		enabled := "unknown"
+		if projection.Enabled != nil {
+			enabled = strconv.FormatBool(*projection.Enabled)
+		}
+		ch <- prometheus.MustNewConstMetric(c.projectionInfo, prometheus.GaugeValue, 1, projection.EffectiveName, projection.Mode, enabled, projection.CheckpointStatus)
+		if reason := projection.FaultReason(); reason != "" {
+			ch <- prometheus.MustNewConstMetric(c.projectionFaultReason, prometheus.GaugeValue, 1, projection.EffectiveName, reason)
+		}
+
 		ch <- prometheus.MustNewConstMetric(c.projectionProgress, prometheus.GaugeValue, projection.Progress/100.0, projection.EffectiveName) // scale to 0-1
 		ch <- prometheus.MustNewConstMetric(c.projectionEventsProcessedAfterRestart, prometheus.CounterValue, float64(projection.EventsProcessedAfterRestart), projection.EffectiveName)
 	}
 }
 
+// collectFromProjectionMetrics exports user-defined metrics, their descriptors depend on config and are not
+// sent by Describe, so they are created here
+func (c *Collector) collectFromProjectionMetrics(ch chan<- prometheus.Metric, values []client.ProjectionMetricValue) {
+	for _, value := range values {
+		help := value.Metric.Help
+		if help == "" {
+			help = "User-defined metric read from projection state or result"
+		}
+
+		desc := prometheus.NewDesc(value.Metric.Name, help, append([]string{"projection", "partition"}, value.Metric.LabelNames()...), nil)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value.Value, append([]string{value.Projection, value.Partition}, value.LabelValues...)...)
+	}
+}
+
 func (c *Collector) collectFromSubscriptionStats(ch chan<- prometheus.Metric, stats []client.SubscriptionStats) {
 	for _, subscription := range stats {
 		ch <- prometheus.MustNewConstMetric(c.subscriptionTotalItemsProcessed, prometheus.CounterValue, float64(subscription.TotalItemsProcessed), subscription.EventStreamID, subscription.GroupName)
@@ -293,6 +667,20 @@ func (c *Collector) collectFromSubscriptionStats(ch chan<- prometheus.Metric, st
 		ch <- prometheus.MustNewConstMetric(c.subscriptionTotalNumberOfParkedMessages, prometheus.GaugeValue, float64(subscription.TotalNumberOfParkedMessages), subscription.EventStreamID, subscription.GroupName)
 		ch <- prometheus.MustNewConstMetric(c.subscriptionOldestParkedMessage, prometheus.GaugeValue, subscription.OldestParkedMessageAgeInSeconds, subscription.EventStreamID, subscription.GroupName)
 
+		if subscription.ParkedMessagesSource != "" {
+			ch <- prometheus.MustNewConstMetric(c.subscriptionParkedMessagesSource, prometheus.GaugeValue, 1, subscription.EventStreamID, subscription.GroupName, subscription.ParkedMessagesSource)
+		}
+
+		if analysis := subscription.ParkedMessagesAnalysis; analysis != nil {
+			ch <- prometheus.MustNewConstHistogram(c.subscriptionParkedMessagesAge, analysis.AnalyzedCount, analysis.AgeSum, analysis.AgeBuckets, subscription.EventStreamID, subscription.GroupName)
+			for eventType, count := range analysis.EventTypes {
+				ch <- prometheus.MustNewConstMetric(c.subscriptionParkedMessagesByEventType, prometheus.GaugeValue, float64(count), subscription.EventStreamID, subscription.GroupName, eventType)
+			}
+			for reason, count := range analysis.ParkReasons {
+				ch <- prometheus.MustNewConstMetric(c.subscriptionParkedMessagesByReason, prometheus.GaugeValue, float64(count), subscription.EventStreamID, subscription.GroupName, reason)
+			}
+		}
+
 		if subscription.EventStreamID == "$all" {
 			lastCheckpointedEventPosition, _, err := client.EventPosition(subscription.LastCheckpointedEventPosition).ParseCommitPreparePosition()
 			if err != nil {
@@ -322,6 +710,20 @@ func (c *Collector) collectFromStreamStats(ch chan<- prometheus.Metric, stats []
 	}
 }
 
+// collectFromStreamMetrics exports user-defined metrics read from the last event of streams, like projection metrics
+// their descriptors depend on config
+func (c *Collector) collectFromStreamMetrics(ch chan<- prometheus.Metric, values []client.StreamMetricValue) {
+	for _, value := range values {
+		help := value.Metric.Help
+		if help == "" {
+			help = "User-defined metric read from the last event in a stream"
+		}
+
+		desc := prometheus.NewDesc(value.Metric.Name, help, append([]string{"stream"}, value.Metric.LabelNames()...), nil)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value.Value, append([]string{value.Stream}, value.LabelValues...)...)
+	}
+}
+
 func (c *Collector) collectFromClusterStats(ch chan<- prometheus.Metric, stats *client.Stats) {
 	isLeader := 0.0
 	if stats.Info.MemberState == client.MemberStateLeader {
@@ -354,8 +756,86 @@ func (c *Collector) collectFromClusterStats(ch chan<- prometheus.Metric, stats *
 			isAlive = 1.0
 		}
 
-		memberName := fmt.Sprintf("%s:%d", member.HTTPEndpointIP, member.HTTPEndpointPort)
+		ch <- prometheus.MustNewConstMetric(c.clusterMemberAlive, prometheus.GaugeValue, isAlive, member.Name())
+	}
+
+	c.collectClockSkew(ch, stats.ClusterMembers)
+
+	c.clusterState.observe(stats.Info.MemberState, stats.ClusterMembers)
+	leaderChanges, stateTransitions := c.clusterState.snapshot()
+
+	ch <- prometheus.MustNewConstMetric(c.clusterLeaderChanges, prometheus.CounterValue, float64(leaderChanges))
+	if epochNumber, ok := clusterEpochNumber(stats.ClusterMembers); ok {
+		ch <- prometheus.MustNewConstMetric(c.clusterEpochNumber, prometheus.GaugeValue, float64(epochNumber))
+	}
+
+	for transition, count := range stateTransitions {
+		ch <- prometheus.MustNewConstMetric(c.memberStateTransitions, prometheus.CounterValue, float64(count), transition.from, transition.to)
+	}
+}
+
+func (c *Collector) collectClockSkew(ch chan<- prometheus.Metric, members []client.MemberStats) {
+	now := time.Now()
+
+	var leaderTimeStamp time.Time
+	for _, member := range members {
+		if member.IsAlive && strings.EqualFold(member.State, client.MemberStateLeader) {
+			leaderTimeStamp = member.TimeStamp.Time
+		}
+	}
+
+	for _, member := range members {
+		if !member.IsAlive || member.TimeStamp.IsZero() {
+			continue
+		}
+
+		skew := c.withinClockSkewTolerance(member.TimeStamp.Sub(now))
+		ch <- prometheus.MustNewConstMetric(c.clusterMemberClockSkew, prometheus.GaugeValue, skew.Seconds(), member.Name(), "exporter")
+
+		if !leaderTimeStamp.IsZero() {
+			skew = c.withinClockSkewTolerance(member.TimeStamp.Sub(leaderTimeStamp))
+			ch <- prometheus.MustNewConstMetric(c.clusterMemberClockSkew, prometheus.GaugeValue, skew.Seconds(), member.Name(), "leader")
+		}
+	}
+}
+
+// gossip timestamps lag behind by up to the gossip interval, so small differences are not treated as skew
+func (c *Collector) withinClockSkewTolerance(skew time.Duration) time.Duration {
+	if skew.Abs() <= c.config().ClockSkewTolerance {
+		return 0
+	}
+
+	return skew
+}
+
+func (c *Collector) collectFromScavengeStats(ch chan<- prometheus.Metric, stats []client.ScavengeStats) {
+	for _, scavenge := range stats {
+		running := 0.0
+		if scavenge.IsRunning {
+			running = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(c.scavengeRunning, prometheus.GaugeValue, running, scavenge.NodeEndpoint)
+
+		if !scavenge.StartTime.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.scavengeLastStartTimestamp, prometheus.GaugeValue, float64(scavenge.StartTime.UnixMilli())/1000.0, scavenge.NodeEndpoint)
+		}
+
+		if scavenge.IsRunning {
+			if !scavenge.StartTime.IsZero() {
+				ch <- prometheus.MustNewConstMetric(c.scavengeLastDuration, prometheus.GaugeValue, time.Since(scavenge.StartTime).Seconds(), scavenge.NodeEndpoint)
+			}
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.scavengeLastEndTimestamp, prometheus.GaugeValue, float64(scavenge.EndTime.UnixMilli())/1000.0, scavenge.NodeEndpoint)
+		ch <- prometheus.MustNewConstMetric(c.scavengeLastDuration, prometheus.GaugeValue, scavenge.TimeTaken.Seconds(), scavenge.NodeEndpoint)
+		ch <- prometheus.MustNewConstMetric(c.scavengeLastResult, prometheus.GaugeValue, 1, scavenge.NodeEndpoint, scavenge.Result)
+		ch <- prometheus.MustNewConstMetric(c.scavengeLastSpaceSaved, prometheus.GaugeValue, float64(scavenge.SpaceSavedBytes), scavenge.NodeEndpoint)
+	}
+}
 
-		ch <- prometheus.MustNewConstMetric(c.clusterMemberAlive, prometheus.GaugeValue, isAlive, memberName)
+func (c *Collector) collectFromTLSCertificateStats(ch chan<- prometheus.Metric, stats []client.TLSCertificateStats) {
+	for _, certificate := range stats {
+		ch <- prometheus.MustNewConstMetric(c.tlsCertificateNotAfter, prometheus.GaugeValue, float64(certificate.NotAfter.Unix()), certificate.Member, certificate.Subject, certificate.Issuer, certificate.Serial)
 	}
 }
@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/client"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func Test_ProjectionStatus_ReportsLegacyAndCurrentStates(t *testing.T) {
+	c := NewCollector(&config.Config{}, nil)
+
+	series := collectSeries(t, func(ch chan<- prometheus.Metric) {
+		c.collectFromProjectionStats(ch, true, []client.ProjectionStats{
+			{EffectiveName: "running", Status: "Running"},
+			{EffectiveName: "preparing", Status: "Preparing", Mode: "Continuous", CheckpointStatus: "Requested"},
+		})
+	})
+
+	expected := map[string]float64{
+		`eventstore_projection_status{projection="running",status="Running"}`:     1,
+		`eventstore_projection_status{projection="running",status="Stopped"}`:     0,
+		`eventstore_projection_status{projection="running",status="Faulted"}`:     0,
+		`eventstore_projection_status{projection="preparing",status="Running"}`:   0,
+		`eventstore_projection_status{projection="preparing",status="Stopped"}`:   0,
+		`eventstore_projection_status{projection="preparing",status="Faulted"}`:   0,
+		`eventstore_projection_status{projection="preparing",status="Preparing"}`: 1,
+	}
+	for name, value := range expected {
+		if actual, found := series[name]; !found || actual != value {
+			t.Errorf("Expected %s to be %v, got %v (found: %t)", name, value, actual, found)
+		}
+	}
+
+	statusSeries := 0
+	for name := range series {
+		if strings.HasPrefix(name, "eventstore_projection_status{") {
+			statusSeries++
+		}
+	}
+	if statusSeries != len(expected) {
+		t.Errorf("Expected %d projection status series, got %d", len(expected), statusSeries)
+	}
+
+	info := `eventstore_projection_info{checkpoint_status="Requested",enabled="unknown",mode="Continuous",projection="preparing"}`
+	if series[info] != 1 {
+		t.Errorf("Expected %s, got series %v", info, series)
+	}
+}
+
+func Test_ClockSkew(t *testing.T) {
+	now := time.Now().UTC()
+	timeStamp := func(offset time.Duration) string {
+		return `"` + now.Add(offset).Format(time.RFC3339Nano) + `"`
+	}
+
+	tests := []struct {
+		name             string
+		memberTimeStamp  string
+		expectedExporter float64
+		expectedLeader   float64
+		expectSkew       bool
+	}{
+		{name: "within tolerance", memberTimeStamp: timeStamp(2 * time.Second), expectedExporter: 0, expectedLeader: 0, expectSkew: true},
+		{name: "out of tolerance", memberTimeStamp: timeStamp(10 * time.Second), expectedExporter: 10, expectedLeader: 10, expectSkew: true},
+		{name: "behind out of tolerance", memberTimeStamp: timeStamp(-10 * time.Second), expectedExporter: -10, expectedLeader: -10, expectSkew: true},
+		{name: "missing time stamp", memberTimeStamp: `null`, expectSkew: false},
+		{name: "unparsable time stamp", memberTimeStamp: `"yesterday"`, expectSkew: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var members []client.MemberStats
+			gossip := `[
+				{"httpEndPointIp": "10.0.0.1", "httpEndPointPort": 2113, "state": "Leader", "isAlive": true, "timeStamp": ` + timeStamp(0) + `},
+				{"httpEndPointIp": "10.0.0.2", "httpEndPointPort": 2113, "state": "Follower", "isAlive": true, "timeStamp": ` + test.memberTimeStamp + `}
+			]`
+			if err := json.Unmarshal([]byte(gossip), &members); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			c := NewCollector(&config.Config{ClockSkewTolerance: 3 * time.Second}, nil)
+			series := collectSeries(t, func(ch chan<- prometheus.Metric) {
+				c.collectClockSkew(ch, members)
+			})
+
+			exporterSkew, foundExporter := series[`eventstore_cluster_member_clock_skew_seconds{member="10.0.0.2:2113",reference="exporter"}`]
+			leaderSkew, foundLeader := series[`eventstore_cluster_member_clock_skew_seconds{member="10.0.0.2:2113",reference="leader"}`]
+
+			if foundExporter != test.expectSkew || foundLeader != test.expectSkew {
+				t.Fatalf("Expected clock skew to be reported: %t, got series %v", test.expectSkew, series)
+			}
+			// reference clock of the exporter moves on during the test
+			if math.Abs(exporterSkew-test.expectedExporter) > 1 {
+				t.Errorf("Expected clock skew to exporter around %v, got %v", test.expectedExporter, exporterSkew)
+			}
+			if leaderSkew != test.expectedLeader {
+				t.Errorf("Expected clock skew to leader %v, got %v", test.expectedLeader, leaderSkew)
+			}
+
+			if _, found := series[`eventstore_cluster_member_clock_skew_seconds{member="10.0.0.1:2113",reference="exporter"}`]; !found {
+				t.Errorf("Expected clock skew of leader to be reported, got series %v", series)
+			}
+		})
+	}
+}
+
+var descNamePattern = regexp.MustCompile(`fqName: "([^"]+)"`)
+
+// collectSeries returns values of collected metrics by series name in the exposition format, labels sorted by name
+func collectSeries(t *testing.T, collect func(ch chan<- prometheus.Metric)) map[string]float64 {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 1000)
+	collect(ch)
+	close(ch)
+
+	series := map[string]float64{}
+	for metric := range ch {
+		written := &dto.Metric{}
+		if err := metric.Write(written); err != nil {
+			t.Fatal(err)
+		}
+
+		labels := make([]string, 0, len(written.GetLabel()))
+		for _, label := range written.GetLabel() {
+			labels = append(labels, fmt.Sprintf("%s=%q", label.GetName(), label.GetValue()))
+		}
+
+		name := descNamePattern.FindStringSubmatch(metric.Desc().String())[1]
+		if len(labels) > 0 {
+			name += "{" + strings.Join(labels, ",") + "}"
+		}
+
+		series[name] = written.GetGauge().GetValue() + written.GetCounter().GetValue()
+	}
+
+	return series
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/client"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedScrapes lets overlapping scrapes of the same target with the same set of enabled stats
+// share a single in-flight GetStats call, so that the load on EventStore doesn't grow with the number of scrapers
+type sharedScrapes struct {
+	group  singleflight.Group
+	shared atomic.Uint64
+}
+
+// getStats runs the shared call with a context detached from the scrape that started it, so that scrapes that
+// joined it don't fail when the first scraper disconnects. The call keeps the deadline of the first scrape
+// (or timeout when it has none), so that EventStore isn't read after the scraper has given up.
+func (scrapes *sharedScrapes) getStats(ctx context.Context, key string, timeout time.Duration, getStats func(context.Context) (*client.Stats, error)) (*client.Stats, error) {
+	executed := false
+	results := scrapes.group.DoChan(key, func() (interface{}, error) {
+		executed = true
+
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadline = time.Now().Add(timeout)
+		}
+
+		sharedCtx, cancel := context.WithDeadline(context.WithoutCancel(ctx), deadline)
+		defer cancel()
+
+		return getStats(sharedCtx)
+	})
+
+	select {
+	case result := <-results:
+		if !executed {
+			scrapes.shared.Add(1)
+		}
+
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		return result.Val.(*client.Stats), nil
+	case <-ctx.Done():
+		// each scrape waits only until its own deadline, the shared call continues for the others
+		return nil, fmt.Errorf("error while waiting for shared scrape: %w", ctx.Err())
+	}
+}
+
+func (scrapes *sharedScrapes) sharedCount() uint64 {
+	return scrapes.shared.Load()
+}
+
+// scrapeKey identifies scrapes that can share results, it includes every setting read by GetStats that changes its result
+func scrapeKey(config *config.Config) string {
+	target := config.EventStoreURL
+	if config.ConnectionString != "" {
+		target = config.ConnectionString
+	}
+
+	return fmt.Sprintf("%s|read-timeout=%s|projections=%s,%t|subscriptions=%s|parked=%t|parked-analysis=%t,%d|tcp=%t|scavenge=%t|tls=%t|streams=%s|projection-metrics=%v|stream-metrics=%v",
+		target,
+		config.ReadTimeout,
+		config.ProjectionStatsSource,
+		config.IncludeOneTimeProjections,
+		config.SubscriptionStatsSource,
+		config.EnableParkedMessagesStats,
+		config.EnableParkedMessagesAnalysis,
+		config.ParkedMessagesAnalysisMaxCount,
+		config.EnableTCPConnectionStats,
+		config.EnableScavengeStats,
+		config.EnableTLSCertificateStats,
+		strings.Join(config.Streams, "\x00"),
+		config.ProjectionMetrics,
+		config.StreamMetrics,
+	)
+}
@@ -0,0 +1,198 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/client"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_SharedScrapes_OverlappingScrapesShareResult(t *testing.T) {
+	scrapes := &sharedScrapes{}
+
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	expected := &client.Stats{}
+
+	getStats := func(context.Context) (*client.Stats, error) {
+		calls.Add(1)
+		close(started)
+		<-release
+		return expected, nil
+	}
+
+	results := make([]*client.Stats, 3)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i > 0 {
+				<-started
+			}
+			results[i], _ = scrapes.getStats(context.Background(), "key", time.Second, getStats)
+		}()
+	}
+
+	<-started
+	time.Sleep(50 * time.Millisecond) // let other scrapes join the in-flight call
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected stats to be fetched once, got %d", calls.Load())
+	}
+	for i, result := range results {
+		if result != expected {
+			t.Errorf("Expected scrape %d to get shared result", i)
+		}
+	}
+	if scrapes.sharedCount() != 2 {
+		t.Errorf("Expected 2 shared scrapes, got %d", scrapes.sharedCount())
+	}
+}
+
+func Test_SharedScrapes_SequentialScrapesNotShared(t *testing.T) {
+	scrapes := &sharedScrapes{}
+
+	var calls atomic.Int32
+	getStats := func(context.Context) (*client.Stats, error) {
+		calls.Add(1)
+		return &client.Stats{}, nil
+	}
+
+	for range 2 {
+		if _, err := scrapes.getStats(context.Background(), "key", time.Second, getStats); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if calls.Load() != 2 || scrapes.sharedCount() != 0 {
+		t.Errorf("Expected 2 separate calls and no shared scrapes, got %d calls, %d shared", calls.Load(), scrapes.sharedCount())
+	}
+}
+
+func Test_SharedScrapes_WaitingScrapeTimesOut(t *testing.T) {
+	scrapes := &sharedScrapes{}
+
+	release := make(chan struct{})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := scrapes.getStats(ctx, "key", time.Second, func(context.Context) (*client.Stats, error) {
+		<-release
+		return &client.Stats{}, nil
+	})
+
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func Test_SharedScrapes_JoinedScrapeSurvivesFirstScrapeCancellation(t *testing.T) {
+	scrapes := &sharedScrapes{}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	expected := &client.Stats{}
+
+	getStats := func(ctx context.Context) (*client.Stats, error) {
+		close(started)
+		select {
+		case <-release:
+			return expected, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstDone := make(chan error)
+	go func() {
+		_, err := scrapes.getStats(firstCtx, "key", time.Second, getStats)
+		firstDone <- err
+	}()
+	<-started
+
+	secondResult := make(chan *client.Stats)
+	go func() {
+		result, _ := scrapes.getStats(context.Background(), "key", time.Second, getStats)
+		secondResult <- result
+	}()
+	time.Sleep(50 * time.Millisecond) // let the second scrape join the in-flight call
+
+	cancelFirst()
+	if err := <-firstDone; err == nil {
+		t.Error("Expected cancelled scrape to fail")
+	}
+
+	close(release)
+	if result := <-secondResult; result != expected {
+		t.Error("Expected joined scrape to get result of the shared call")
+	}
+}
+
+func Test_SharedScrapes_SharedCallKeepsScrapeDeadline(t *testing.T) {
+	scrapes := &sharedScrapes{}
+
+	// deadline derived from the scrape timeout header is shorter than the configured timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	expected, _ := ctx.Deadline()
+
+	var deadline time.Time
+	_, err := scrapes.getStats(ctx, "key", time.Minute, func(ctx context.Context) (*client.Stats, error) {
+		deadline, _ = ctx.Deadline()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+	if !deadline.Equal(expected) {
+		t.Errorf("Expected shared call deadline %v, got %v", expected, deadline)
+	}
+}
+
+func Test_SharedScrapes_SharedCallLimitedByTimeoutWithoutDeadline(t *testing.T) {
+	scrapes := &sharedScrapes{}
+
+	_, err := scrapes.getStats(context.Background(), "key", 50*time.Millisecond, func(ctx context.Context) (*client.Stats, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func Test_ScrapeKey(t *testing.T) {
+	base := &config.Config{EventStoreURL: "http://localhost:2113", Streams: []string{"a"}}
+	same := &config.Config{EventStoreURL: "http://localhost:2113", Streams: []string{"a"}, ClockSkewTolerance: time.Second}
+	otherStreams := &config.Config{EventStoreURL: "http://localhost:2113", Streams: []string{"b"}}
+	otherCollectors := &config.Config{EventStoreURL: "http://localhost:2113", Streams: []string{"a"}, EnableScavengeStats: true}
+	otherSource := &config.Config{EventStoreURL: "http://localhost:2113", Streams: []string{"a"}, ProjectionStatsSource: config.StatsSourceGRPC}
+	otherOneTimeProjections := &config.Config{EventStoreURL: "http://localhost:2113", Streams: []string{"a"}, IncludeOneTimeProjections: true}
+	otherProjectionMetrics := &config.Config{EventStoreURL: "http://localhost:2113", Streams: []string{"a"}, ProjectionMetrics: []config.ProjectionMetrics{
+		{Projection: "orders", Source: config.ProjectionMetricsSourceState, Metrics: []config.JSONMetric{{Name: "orders_total", Path: "$.total"}}},
+	}}
+
+	if scrapeKey(base) != scrapeKey(same) {
+		t.Error("Expected same key for configs with same target and collectors")
+	}
+	if scrapeKey(base) == scrapeKey(otherStreams) || scrapeKey(base) == scrapeKey(otherCollectors) || scrapeKey(base) == scrapeKey(otherProjectionMetrics) {
+		t.Error("Expected different keys for different streams or collectors")
+	}
+	if scrapeKey(base) == scrapeKey(otherSource) || scrapeKey(base) == scrapeKey(otherOneTimeProjections) {
+		t.Error("Expected different keys for different projection settings")
+	}
+}
@@ -3,25 +3,62 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
 	"github.com/namsral/flag"
 )
 
+// Sources of stats that can be read either over HTTP API or over gRPC
+const (
+	StatsSourceAuto = "auto"
+	StatsSourceHTTP = "http"
+	StatsSourceGRPC = "grpc"
+)
+
 type Config struct {
-	Timeout            time.Duration
-	Port               uint
-	Verbose            bool
-	InsecureSkipVerify bool
-
-	EventStoreURL             string
-	EventStoreUser            string
-	EventStorePassword        string
-	EnableParkedMessagesStats bool
-	Streams                   []string
-	StreamsSeparator          string
-	EnableTCPConnectionStats  bool
+	Timeout             time.Duration `yaml:"timeout"`
+	ScrapeTimeoutOffset time.Duration `yaml:"scrape-timeout-offset"`
+	MaxConcurrentReads  uint          `yaml:"max-concurrent-reads"`
+	ReadTimeout         time.Duration `yaml:"read-timeout"`
+	Port                uint          `yaml:"port"`
+	Verbose             bool          `yaml:"verbose"`
+	InsecureSkipVerify  bool          `yaml:"insecure-skip-verify"`
+
+	EventStoreURL                  string              `yaml:"eventstore-url,omitempty"`
+	ConnectionString               string              `yaml:"connection-string"`
+	EventStoreUser                 string              `yaml:"eventstore-user"`
+	EventStorePassword             string              `yaml:"eventstore-password"`
+	EventStoreUserFile             string              `yaml:"eventstore-user-file"`
+	EventStorePasswordFile         string              `yaml:"eventstore-password-file"`
+	EventStoreBearerToken          string              `yaml:"eventstore-bearer-token"`
+	EventStoreBearerTokenFile      string              `yaml:"eventstore-bearer-token-file"`
+	OAuthTokenURL                  string              `yaml:"oauth-token-url"`
+	OAuthClientID                  string              `yaml:"oauth-client-id"`
+	OAuthClientSecret              string              `yaml:"oauth-client-secret"`
+	OAuthScopes                    string              `yaml:"oauth-scopes"`
+	OAuthCAFile                    string              `yaml:"oauth-ca-file"`
+	EventStoreCAFile               string              `yaml:"eventstore-ca-file"`
+	EventStoreClientCert           string              `yaml:"eventstore-client-cert"`
+	EventStoreClientKey            string              `yaml:"eventstore-client-key"`
+	ProjectionStatsSource          string              `yaml:"projection-stats-source"`
+	IncludeOneTimeProjections      bool                `yaml:"include-one-time-projections"`
+	ProjectionMetrics              []ProjectionMetrics `yaml:"projection-metrics,omitempty"`
+	SubscriptionStatsSource        string              `yaml:"subscription-stats-source"`
+	EnableParkedMessagesStats      bool                `yaml:"enable-parked-messages-stats"`
+	EnableParkedMessagesAnalysis   bool                `yaml:"enable-parked-messages-analysis"`
+	ParkedMessagesAnalysisMaxCount uint                `yaml:"parked-messages-analysis-max-count"`
+	Streams                        []string            `yaml:"streams"`
+	StreamMetrics                  []StreamMetrics     `yaml:"stream-metrics,omitempty"`
+	StreamsSeparator               string              `yaml:"streams-separator"`
+	EnableTCPConnectionStats       bool                `yaml:"enable-tcp-connection-stats"`
+	EnableScavengeStats            bool                `yaml:"enable-scavenge-stats"`
+	EnableTLSCertificateStats      bool                `yaml:"enable-tls-certificate-stats"`
+	ClockSkewTolerance             time.Duration       `yaml:"clock-skew-tolerance"`
+	MetricsVersion                 uint                `yaml:"metrics-version"`
+	EnableLegacyGaugeMetrics       bool                `yaml:"enable-legacy-gauge-metrics"`
+	PrintConfig                    bool                `yaml:"-"`
 }
 
 func Load(args []string, suppressOutput bool) (*Config, error) {
@@ -29,47 +66,221 @@ func Load(args []string, suppressOutput bool) (*Config, error) {
 
 	fs := flag.NewFlagSet("flagset", flag.ContinueOnError)
 	fs.String(flag.DefaultConfigFlagname, "", "Path to config file")
+	fs.String(yamlConfigFlagname, "", "Path to YAML or JSON config file")
+	fs.BoolVar(&config.PrintConfig, "print-config", false, "Print effective configuration with secrets redacted and exit")
 	fs.StringVar(&config.EventStoreURL, "eventstore-url", "http://localhost:2113", "EventStore URL")
+	fs.StringVar(&config.ConnectionString, "connection-string", "", "EventStore connection string, alternative to EventStore URL")
 	fs.StringVar(&config.EventStoreUser, "eventstore-user", "", "EventStore User")
 	fs.StringVar(&config.EventStorePassword, "eventstore-password", "", "EventStore Password")
+	fs.StringVar(&config.EventStoreUserFile, "eventstore-user-file", "", "Path to file containing EventStore User")
+	fs.StringVar(&config.EventStorePasswordFile, "eventstore-password-file", "", "Path to file containing EventStore Password")
+	fs.StringVar(&config.EventStoreBearerToken, "eventstore-bearer-token", "", "Bearer token used to authenticate with EventStore")
+	fs.StringVar(&config.EventStoreBearerTokenFile, "eventstore-bearer-token-file", "", "Path to file containing bearer token used to authenticate with EventStore")
+	fs.StringVar(&config.OAuthTokenURL, "oauth-token-url", "", "OAuth2 token endpoint used to get access tokens with client credentials flow")
+	fs.StringVar(&config.OAuthClientID, "oauth-client-id", "", "OAuth2 client ID")
+	fs.StringVar(&config.OAuthClientSecret, "oauth-client-secret", "", "OAuth2 client secret")
+	fs.StringVar(&config.OAuthScopes, "oauth-scopes", "", "Space separated OAuth2 scopes to request")
+	fs.StringVar(&config.OAuthCAFile, "oauth-ca-file", "", "Path to PEM CA certificate used to verify OAuth2 token endpoint instead of system CAs")
+	fs.StringVar(&config.EventStoreCAFile, "eventstore-ca-file", "", "Path to CA certificate bundle used to verify EventStore TLS certificates")
+	fs.StringVar(&config.EventStoreClientCert, "eventstore-client-cert", "", "Path to client certificate used to authenticate with EventStore")
+	fs.StringVar(&config.EventStoreClientKey, "eventstore-client-key", "", "Path to private key of the client certificate")
 	fs.UintVar(&config.Port, "port", 9448, "Port to expose scraping endpoint on")
 	fs.DurationVar(&config.Timeout, "timeout", time.Second*8, "Timeout for the scrape operation")
+	fs.DurationVar(&config.ScrapeTimeoutOffset, "scrape-timeout-offset", time.Millisecond*500, "Subtracted from the scrape timeout sent by Prometheus to get deadline for the scrape")
+	fs.UintVar(&config.MaxConcurrentReads, "max-concurrent-reads", 20, "Maximum number of per-stream and per-subscription parked messages reads executed concurrently, other calls are not limited")
+	fs.DurationVar(&config.ReadTimeout, "read-timeout", time.Second*3, "Timeout for a single stream or parked messages read")
 	fs.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
 	fs.BoolVar(&config.InsecureSkipVerify, "insecure-skip-verify", false, "Skip TLS certificatte verification for EventStore HTTP client")
+	fs.StringVar(&config.ProjectionStatsSource, "projection-stats-source", StatsSourceAuto, "Source of projection stats: http, grpc or auto to use grpc when HTTP projection endpoints are unavailable")
+	fs.BoolVar(&config.IncludeOneTimeProjections, "include-one-time-projections", false, "Include one-time and transient projections in projection stats")
+	fs.StringVar(&config.SubscriptionStatsSource, "subscription-stats-source", StatsSourceAuto, "Source of subscription stats: http, grpc or auto to use grpc when AtomPub is disabled")
 	fs.BoolVar(&config.EnableParkedMessagesStats, "enable-parked-messages-stats", false, "Enable parked messages stats scraping")
+	fs.BoolVar(&config.EnableParkedMessagesAnalysis, "enable-parked-messages-analysis", false, "Enable analysis of parked message ages, event types and park reasons, requires parked messages stats")
+	fs.UintVar(&config.ParkedMessagesAnalysisMaxCount, "parked-messages-analysis-max-count", 1000, "Maximum number of parked messages read per subscription by parked messages analysis")
 	streamsString := fs.String("streams", "", "List of streams to get metrics for")
 	fs.StringVar(&config.StreamsSeparator, "streams-separator", ",", "Separator for streams list (default: ',')")
 	fs.BoolVar(&config.EnableTCPConnectionStats, "enable-tcp-connection-stats", false, "Enable TCP connection stats scraping")
+	fs.BoolVar(&config.EnableScavengeStats, "enable-scavenge-stats", false, "Enable scavenge stats scraping")
+	fs.BoolVar(&config.EnableTLSCertificateStats, "enable-tls-certificate-stats", false, "Enable TLS certificate expiry stats of cluster members")
+	fs.UintVar(&config.MetricsVersion, "metrics-version", 1, "Version of the exported metric set, 2 exports cumulative values as counters")
+	fs.BoolVar(&config.EnableLegacyGaugeMetrics, "enable-legacy-gauge-metrics", false, "Also export version 1 gauges replaced by counters in metric set version 2")
+	fs.DurationVar(&config.ClockSkewTolerance, "clock-skew-tolerance", time.Second*3, "Clock differences between cluster members below this value are reported as 0")
 
 	if suppressOutput {
 		fs.Usage = func() {}
 	}
 
+	var yamlConfig *yamlConfig
+	if path := findYAMLConfigFile(args); path != "" {
+		var err error
+		yamlConfig, err = loadYAMLConfig(path, fs)
+		if err != nil {
+			return nil, err
+		}
+
+		err = yamlConfig.apply(fs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err := fs.Parse(args)
 	if err != nil {
 		return nil, err
 	}
 
+	explicitFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
+	overriddenYAMLValues := maps.Clone(explicitFlags)
+
 	config.Streams = parseStreamList(streamsString, config.StreamsSeparator)
+	if yamlConfig != nil {
+		for name := range yamlConfig.values {
+			explicitFlags[name] = true
+		}
+
+		if yamlConfig.streams != nil && !explicitFlags["streams"] {
+			config.Streams = yamlConfig.streams
+		}
 
-	err = config.validate()
+		config.ProjectionMetrics = yamlConfig.projectionMetrics
+		config.StreamMetrics = yamlConfig.streamMetrics
+	}
+
+	if config.ConnectionString != "" {
+		err = config.applyConnectionString(explicitFlags)
+	}
+
+	err = errors.Join(err, config.validate(explicitFlags))
 	if err != nil {
+		if yamlConfig != nil {
+			err = yamlConfig.errorWithPosition(err, overriddenYAMLValues)
+		}
 		return nil, err
 	}
 
 	return config, nil
 }
 
-func (config *Config) validate() error {
-	if (config.EventStoreUser != "" && config.EventStorePassword == "") || (config.EventStoreUser == "" && config.EventStorePassword != "") {
-		return errors.New("EventStore user and password should both be specified, or should both be empty")
+// validate checks all settings and returns all problems found, not only the first one
+func (config *Config) validate(explicitFlags map[string]bool) error {
+	var errs []error
+
+	if config.EventStoreUser != "" && config.EventStoreUserFile != "" {
+		errs = append(errs, invalidSetting(errors.New("EventStore user and user file should not both be specified"), "eventstore-user", "eventstore-user-file"))
+	}
+
+	if config.EventStorePassword != "" && config.EventStorePasswordFile != "" {
+		errs = append(errs, invalidSetting(errors.New("EventStore password and password file should not both be specified"), "eventstore-password", "eventstore-password-file"))
+	}
+
+	hasUser := config.EventStoreUser != "" || config.EventStoreUserFile != ""
+	hasPassword := config.EventStorePassword != "" || config.EventStorePasswordFile != ""
+	if hasUser != hasPassword {
+		errs = append(errs, invalidSetting(errors.New("EventStore user and password should both be specified, or should both be empty"), "eventstore-user", "eventstore-user-file", "eventstore-password", "eventstore-password-file"))
+	}
+
+	authMethods := 0
+	for _, configured := range []bool{hasUser, config.EventStoreBearerToken != "", config.EventStoreBearerTokenFile != "", config.OAuthTokenURL != ""} {
+		if configured {
+			authMethods++
+		}
+	}
+	if authMethods > 1 {
+		errs = append(errs, invalidSetting(errors.New("only one of EventStore user and password, bearer token, bearer token file or OAuth token URL should be specified"), "eventstore-user", "eventstore-user-file", "eventstore-bearer-token", "eventstore-bearer-token-file", "oauth-token-url"))
+	}
+
+	if config.OAuthTokenURL != "" && (config.OAuthClientID == "" || config.OAuthClientSecret == "") {
+		errs = append(errs, invalidSetting(errors.New("OAuth client ID and client secret should be specified when OAuth token URL is specified"), "oauth-token-url"))
+	}
+
+	if config.OAuthCAFile != "" && config.OAuthTokenURL == "" {
+		errs = append(errs, invalidSetting(errors.New("OAuth CA file should only be specified together with OAuth token URL"), "oauth-ca-file"))
+	}
+
+	if (config.EventStoreClientCert != "") != (config.EventStoreClientKey != "") {
+		errs = append(errs, invalidSetting(errors.New("EventStore client certificate and key should both be specified, or should both be empty"), "eventstore-client-cert", "eventstore-client-key"))
+	}
+
+	if !isValidStatsSource(config.ProjectionStatsSource) {
+		errs = append(errs, invalidSetting(fmt.Errorf("projection stats source should be auto, http or grpc, got %s", config.ProjectionStatsSource), "projection-stats-source"))
+	}
+
+	if !isValidStatsSource(config.SubscriptionStatsSource) {
+		errs = append(errs, invalidSetting(fmt.Errorf("subscription stats source should be auto, http or grpc, got %s", config.SubscriptionStatsSource), "subscription-stats-source"))
 	}
 
+	if config.EnableParkedMessagesAnalysis && !config.EnableParkedMessagesStats {
+		errs = append(errs, invalidSetting(errors.New("parked messages analysis requires parked messages stats to be enabled"), "enable-parked-messages-analysis", "enable-parked-messages-stats"))
+	}
+
+	if config.ParkedMessagesAnalysisMaxCount == 0 {
+		errs = append(errs, invalidSetting(errors.New("parked messages analysis max count should be at least 1"), "parked-messages-analysis-max-count"))
+	}
+
+	errs = append(errs, config.validateProjectionMetrics()...)
+	errs = append(errs, config.validateStreamMetrics()...)
+
 	if len(config.StreamsSeparator) != 1 {
-		return fmt.Errorf("streams separator should be a single character, got %s", config.StreamsSeparator)
+		errs = append(errs, invalidSetting(fmt.Errorf("streams separator should be a single character, got %s", config.StreamsSeparator), "streams-separator"))
+	}
+
+	if config.MetricsVersion != 1 && config.MetricsVersion != 2 {
+		errs = append(errs, invalidSetting(fmt.Errorf("metrics version should be 1 or 2, got %d", config.MetricsVersion), "metrics-version"))
+	}
+
+	if config.ScrapeTimeoutOffset < 0 {
+		errs = append(errs, invalidSetting(fmt.Errorf("scrape timeout offset should not be negative, got %s", config.ScrapeTimeoutOffset), "scrape-timeout-offset"))
+	}
+
+	if config.MaxConcurrentReads == 0 {
+		errs = append(errs, invalidSetting(errors.New("max concurrent reads should be at least 1"), "max-concurrent-reads"))
+	}
+
+	if config.ReadTimeout <= 0 {
+		errs = append(errs, invalidSetting(fmt.Errorf("read timeout should be positive, got %s", config.ReadTimeout), "read-timeout"))
+	}
+
+	// a read that outlives the scrape would only keep its read slot after the scrape has already failed, the default
+	// read timeout is not checked, so that configs with a short timeout that predate read timeout keep working
+	if explicitFlags["read-timeout"] && config.ReadTimeout >= config.Timeout {
+		errs = append(errs, invalidSetting(fmt.Errorf("read timeout should be shorter than timeout %s, got %s", config.Timeout, config.ReadTimeout), "read-timeout", "timeout"))
 	}
 
-	return nil
+	if config.ClockSkewTolerance < 0 {
+		errs = append(errs, invalidSetting(fmt.Errorf("clock skew tolerance should not be negative, got %s", config.ClockSkewTolerance), "clock-skew-tolerance"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// settingError is a validation error caused by values of the given settings, it allows the error
+// to be reported with position of the setting in YAML config file
+type settingError struct {
+	settings []string
+	// item of a list setting that caused the error, -1 when it's caused by the whole setting
+	item int
+	err  error
+}
+
+func invalidSetting(err error, settings ...string) error {
+	return &settingError{settings: settings, item: -1, err: err}
+}
+
+func invalidSettingItem(setting string, item int, err error) error {
+	return &settingError{settings: []string{setting}, item: item, err: err}
+}
+
+func (err *settingError) Error() string {
+	return err.err.Error()
+}
+
+func (err *settingError) Unwrap() error {
+	return err.err
+}
+
+func isValidStatsSource(source string) bool {
+	return source == StatsSourceAuto || source == StatsSourceHTTP || source == StatsSourceGRPC
 }
 
 func parseStreamList(streamsString *string, streamsSeparator string) []string {
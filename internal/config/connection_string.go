@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+)
+
+// NormalizedConnectionString returns connection string with kurrentdb:// scheme rewritten to esdb:// understood by the esdb client
+func (config *Config) NormalizedConnectionString() string {
+	connectionString := config.ConnectionString
+
+	scheme, rest, found := strings.Cut(connectionString, "://")
+	if !found {
+		return connectionString
+	}
+
+	switch strings.ToLower(scheme) {
+	case "kurrentdb", "kurrent", "kdb":
+		return "esdb://" + rest
+	case "kurrentdb+discover", "kurrent+discover", "kdb+discover":
+		return "esdb+discover://" + rest
+	default:
+		return connectionString
+	}
+}
+
+// applyConnectionString fills EventStore URL, credentials and TLS settings from the connection string,
+// so that HTTP calls use the same nodes and settings as gRPC calls
+func (config *Config) applyConnectionString(explicitFlags map[string]bool) error {
+	esConfig, err := esdb.ParseConnectionString(config.NormalizedConnectionString())
+	if err != nil {
+		return invalidSetting(fmt.Errorf("invalid connection string: %w", err), "connection-string")
+	}
+
+	if explicitFlags["eventstore-url"] {
+		return invalidSetting(errors.New("EventStore URL and connection string should not both be specified"), "connection-string", "eventstore-url")
+	}
+
+	scheme := "https"
+	if esConfig.DisableTLS {
+		scheme = "http"
+	}
+
+	host := esConfig.Address
+	if len(esConfig.GossipSeeds) > 0 {
+		host = esConfig.GossipSeeds[0].String()
+	}
+
+	config.EventStoreURL = scheme + "://" + host
+
+	if esConfig.Username != "" {
+		if config.EventStoreUser != "" || config.EventStoreUserFile != "" || config.EventStorePassword != "" || config.EventStorePasswordFile != "" {
+			return invalidSetting(errors.New("EventStore credentials should not be specified both in connection string and separately"), "connection-string")
+		}
+
+		config.EventStoreUser = esConfig.Username
+		config.EventStorePassword = esConfig.Password
+	}
+
+	if esConfig.SkipCertificateVerification {
+		config.InsecureSkipVerify = true
+	}
+
+	settings := connectionStringSettings(config.ConnectionString)
+	if config.EventStoreCAFile == "" {
+		config.EventStoreCAFile = settings["tlscafile"]
+	}
+	if config.EventStoreClientCert == "" && config.EventStoreClientKey == "" {
+		config.EventStoreClientCert = settings["usercertfile"]
+		config.EventStoreClientKey = settings["userkeyfile"]
+	}
+
+	return nil
+}
+
+// withoutConnectionStringSettings returns copy of the config without settings filled by applyConnectionString,
+// so that the config can be loaded again together with the connection string
+func (config *Config) withoutConnectionStringSettings() *Config {
+	result := *config
+	if config.ConnectionString == "" {
+		return &result
+	}
+
+	esConfig, err := esdb.ParseConnectionString(config.NormalizedConnectionString())
+	if err != nil {
+		return &result
+	}
+
+	result.EventStoreURL = ""
+
+	if esConfig.Username != "" {
+		result.EventStoreUser = ""
+		result.EventStorePassword = ""
+	}
+
+	if esConfig.SkipCertificateVerification {
+		result.InsecureSkipVerify = false
+	}
+
+	settings := connectionStringSettings(config.ConnectionString)
+	if config.EventStoreCAFile == settings["tlscafile"] {
+		result.EventStoreCAFile = ""
+	}
+	if config.EventStoreClientCert == settings["usercertfile"] && config.EventStoreClientKey == settings["userkeyfile"] {
+		result.EventStoreClientCert = ""
+		result.EventStoreClientKey = ""
+	}
+
+	return &result
+}
+
+// connectionStringSettings returns query settings of the connection string with lower cased keys
+func connectionStringSettings(connectionString string) map[string]string {
+	settings := map[string]string{}
+
+	_, query, found := strings.Cut(connectionString, "?")
+	if !found {
+		return settings
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return settings
+	}
+
+	for key, value := range values {
+		if len(value) > 0 {
+			settings[strings.ToLower(key)] = value[0]
+		}
+	}
+
+	return settings
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/jsonpath"
+	"github.com/prometheus/common/model"
+)
+
+// names of metrics exported by the exporter itself start with this prefix, user-defined metrics can't use it
+const reservedMetricPrefix = "eventstore_"
+
+// JSONMetric is a gauge with values selected by path from a JSON document. Keys matched by wildcards
+// of the path are used as values of labels, in order. Label fields map additional labels to paths
+// of string values in the same document.
+type JSONMetric struct {
+	Name        string            `yaml:"name"`
+	Help        string            `yaml:"help,omitempty"`
+	Path        string            `yaml:"path"`
+	Labels      []string          `yaml:"labels,omitempty"`
+	LabelFields map[string]string `yaml:"label-fields,omitempty"`
+}
+
+// LabelNames returns names of user-defined labels of the metric, wildcard labels first, followed by label fields
+// sorted by name
+func (metric *JSONMetric) LabelNames() []string {
+	fields := make([]string, 0, len(metric.LabelFields))
+	for label := range metric.LabelFields {
+		fields = append(fields, label)
+	}
+	sort.Strings(fields)
+
+	return append(slices.Clone(metric.Labels), fields...)
+}
+
+// sameAs returns true when both metrics can be exported under the same name
+func (metric *JSONMetric) sameAs(other JSONMetric) bool {
+	return metric.Help == other.Help && slices.Equal(metric.LabelNames(), other.LabelNames())
+}
+
+func (metric *JSONMetric) validate(reservedLabels []string) error {
+	if !model.IsValidLegacyMetricName(metric.Name) {
+		return fmt.Errorf("%q is not a valid metric name", metric.Name)
+	}
+
+	if strings.HasPrefix(metric.Name, reservedMetricPrefix) {
+		return fmt.Errorf("metric %s should not start with %s, which is reserved for metrics of the exporter", metric.Name, reservedMetricPrefix)
+	}
+
+	path, err := jsonpath.Compile(metric.Path)
+	if err != nil {
+		return fmt.Errorf("metric %s: %w", metric.Name, err)
+	}
+
+	if path.Wildcards() != len(metric.Labels) {
+		return fmt.Errorf("metric %s should have one label for each of %d wildcards of its path, got %d labels", metric.Name, path.Wildcards(), len(metric.Labels))
+	}
+
+	labels := metric.LabelNames()
+	for i, label := range labels {
+		if !model.LabelName(label).IsValidLegacy() || slices.Contains(reservedLabels, label) || slices.Contains(labels[:i], label) {
+			return fmt.Errorf("metric %s has invalid or duplicate label %q", metric.Name, label)
+		}
+	}
+
+	for label, fieldPath := range metric.LabelFields {
+		path, err := jsonpath.Compile(fieldPath)
+		if err != nil {
+			return fmt.Errorf("label %s of metric %s: %w", label, metric.Name, err)
+		}
+
+		if path.Wildcards() > 0 {
+			return fmt.Errorf("path of label %s of metric %s should select a single value, without wildcards", label, metric.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "**REDACTED**"
+
+// Redacted returns copy of the config with secrets replaced, safe to log or print
+func (config *Config) Redacted() *Config {
+	redacted := *config
+
+	redacted.EventStorePassword = redact(config.EventStorePassword)
+	redacted.EventStoreBearerToken = redact(config.EventStoreBearerToken)
+	redacted.OAuthClientSecret = redact(config.OAuthClientSecret)
+	redacted.ConnectionString = redactConnectionString(config.ConnectionString)
+
+	return &redacted
+}
+
+// Print writes effective configuration with secrets redacted, in the YAML config file format. Settings derived
+// from connection string are left out, the printed config would be rejected otherwise.
+func (config *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	defer encoder.Close()
+
+	return encoder.Encode(config.withoutConnectionStringSettings().Redacted())
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return secret
+	}
+
+	return redactedValue
+}
+
+func redactConnectionString(connectionString string) string {
+	scheme, rest, found := strings.Cut(connectionString, "://")
+	credentials, hosts, hasCredentials := strings.Cut(rest, "@")
+	if !found || !hasCredentials {
+		return connectionString
+	}
+
+	user, password, _ := strings.Cut(credentials, ":")
+	return scheme + "://" + user + ":" + redact(password) + "@" + hosts
+}
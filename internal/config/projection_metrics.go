@@ -0,0 +1,96 @@
+package config
+
+import "fmt"
+
+const projectionMetricsKey = "projection-metrics"
+
+// Documents of a projection that projection metrics can be read from
+const (
+	ProjectionMetricsSourceState  = "state"
+	ProjectionMetricsSourceResult = "result"
+)
+
+// labels added to every projection metric, user-defined labels can't use these names
+var projectionMetricLabels = []string{"projection", "partition"}
+
+// ProjectionMetrics maps values in state or result of a projection to gauges
+type ProjectionMetrics struct {
+	Projection string       `yaml:"projection"`
+	Partition  string       `yaml:"partition,omitempty"`
+	Source     string       `yaml:"source"`
+	Metrics    []JSONMetric `yaml:"metrics"`
+}
+
+// validateProjectionMetrics returns problems of all projection metrics, an item with invalid
+// projection settings is not checked further
+func (config *Config) validateProjectionMetrics() []error {
+	var errs []error
+	invalid := func(item int, format string, args ...any) {
+		errs = append(errs, invalidSettingItem(projectionMetricsKey, item, fmt.Errorf(format, args...)))
+	}
+
+	metricsByName := map[string]JSONMetric{}
+	documents := map[string]bool{}
+	// series of a metric are told apart by projection and partition labels, so a metric can be read once per partition
+	partitionsByName := map[string]bool{}
+
+	for i := range config.ProjectionMetrics {
+		projectionMetrics := &config.ProjectionMetrics[i]
+
+		if projectionMetrics.Projection == "" {
+			invalid(i, "projection metrics #%d should specify projection name", i+1)
+			continue
+		}
+
+		if projectionMetrics.Source == "" {
+			projectionMetrics.Source = ProjectionMetricsSourceState
+		}
+		if projectionMetrics.Source != ProjectionMetricsSourceState && projectionMetrics.Source != ProjectionMetricsSourceResult {
+			invalid(i, "source of projection %s metrics should be state or result, got %s", projectionMetrics.Projection, projectionMetrics.Source)
+			continue
+		}
+
+		// metrics read from the same document should be listed together, otherwise series could be duplicated
+		document := projectionMetrics.Projection + "\x00" + projectionMetrics.Partition + "\x00" + projectionMetrics.Source
+		if documents[document] {
+			invalid(i, "%s of projection %s partition %q is listed more than once in projection metrics", projectionMetrics.Source, projectionMetrics.Projection, projectionMetrics.Partition)
+			continue
+		}
+		documents[document] = true
+
+		if len(projectionMetrics.Metrics) == 0 {
+			invalid(i, "projection %s metrics should specify at least one metric", projectionMetrics.Projection)
+			continue
+		}
+
+		names := map[string]bool{}
+		for _, metric := range projectionMetrics.Metrics {
+			if names[metric.Name] {
+				invalid(i, "metric %s is listed more than once for projection %s", metric.Name, projectionMetrics.Projection)
+				continue
+			}
+			names[metric.Name] = true
+
+			if err := metric.validate(projectionMetricLabels); err != nil {
+				invalid(i, "invalid metric of projection %s: %w", projectionMetrics.Projection, err)
+				continue
+			}
+
+			// the same metric can be read from several projections or partitions, but its help and labels should not change
+			if other, exists := metricsByName[metric.Name]; exists && !other.sameAs(metric) {
+				invalid(i, "projection metric %s should have the same help and labels wherever it is used", metric.Name)
+				continue
+			}
+			metricsByName[metric.Name] = metric
+
+			partition := metric.Name + "\x00" + projectionMetrics.Projection + "\x00" + projectionMetrics.Partition
+			if partitionsByName[partition] {
+				invalid(i, "projection metric %s is read more than once from projection %s partition %q", metric.Name, projectionMetrics.Projection, projectionMetrics.Partition)
+				continue
+			}
+			partitionsByName[partition] = true
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,49 @@
+package config
+
+// connectionSettings are settings that require EventStore connection to be recreated when they change
+type connectionSettings struct {
+	eventStoreURL             string
+	connectionString          string
+	insecureSkipVerify        bool
+	eventStoreUser            string
+	eventStorePassword        string
+	eventStoreUserFile        string
+	eventStorePasswordFile    string
+	eventStoreBearerToken     string
+	eventStoreBearerTokenFile string
+	oauthTokenURL             string
+	oauthClientID             string
+	oauthClientSecret         string
+	oauthScopes               string
+	oauthCAFile               string
+	eventStoreCAFile          string
+	eventStoreClientCert      string
+	eventStoreClientKey       string
+}
+
+func (config *Config) connectionSettings() connectionSettings {
+	return connectionSettings{
+		eventStoreURL:             config.EventStoreURL,
+		connectionString:          config.ConnectionString,
+		insecureSkipVerify:        config.InsecureSkipVerify,
+		eventStoreUser:            config.EventStoreUser,
+		eventStorePassword:        config.EventStorePassword,
+		eventStoreUserFile:        config.EventStoreUserFile,
+		eventStorePasswordFile:    config.EventStorePasswordFile,
+		eventStoreBearerToken:     config.EventStoreBearerToken,
+		eventStoreBearerTokenFile: config.EventStoreBearerTokenFile,
+		oauthTokenURL:             config.OAuthTokenURL,
+		oauthClientID:             config.OAuthClientID,
+		oauthClientSecret:         config.OAuthClientSecret,
+		oauthScopes:               config.OAuthScopes,
+		oauthCAFile:               config.OAuthCAFile,
+		eventStoreCAFile:          config.EventStoreCAFile,
+		eventStoreClientCert:      config.EventStoreClientCert,
+		eventStoreClientKey:       config.EventStoreClientKey,
+	}
+}
+
+// ConnectionSettingsEqual returns true when both configs connect to EventStore in the same way
+func (config *Config) ConnectionSettingsEqual(other *Config) bool {
+	return config.connectionSettings() == other.connectionSettings()
+}
@@ -0,0 +1,107 @@
+package config
+
+import "fmt"
+
+const streamMetricsKey = "stream-metrics"
+
+// Parts of the last event in a stream that stream metrics can be read from
+const (
+	StreamMetricsSourceData     = "data"
+	StreamMetricsSourceMetadata = "metadata"
+)
+
+// labels added to every stream metric, user-defined labels can't use these names
+var streamMetricLabels = []string{"stream"}
+
+// StreamMetrics maps values in JSON data or metadata of the last event in a stream to gauges
+type StreamMetrics struct {
+	Stream  string       `yaml:"stream"`
+	Source  string       `yaml:"source"`
+	Metrics []JSONMetric `yaml:"metrics"`
+}
+
+// validateStreamMetrics returns problems of all stream metrics, an item with invalid
+// stream settings is not checked further
+func (config *Config) validateStreamMetrics() []error {
+	var errs []error
+	invalid := func(item int, format string, args ...any) {
+		errs = append(errs, invalidSettingItem(streamMetricsKey, item, fmt.Errorf(format, args...)))
+	}
+
+	projectionMetricNames := map[string]bool{}
+	for _, projectionMetrics := range config.ProjectionMetrics {
+		for _, metric := range projectionMetrics.Metrics {
+			projectionMetricNames[metric.Name] = true
+		}
+	}
+
+	metricsByName := map[string]JSONMetric{}
+	streams := map[string]bool{}
+	// series of a metric are told apart by stream label, so a metric can be read once per stream
+	streamsByName := map[string]bool{}
+
+	for i := range config.StreamMetrics {
+		streamMetrics := &config.StreamMetrics[i]
+
+		if streamMetrics.Stream == "" {
+			invalid(i, "stream metrics #%d should specify stream name", i+1)
+			continue
+		}
+
+		if streamMetrics.Source == "" {
+			streamMetrics.Source = StreamMetricsSourceData
+		}
+		if streamMetrics.Source != StreamMetricsSourceData && streamMetrics.Source != StreamMetricsSourceMetadata {
+			invalid(i, "source of stream %s metrics should be data or metadata, got %s", streamMetrics.Stream, streamMetrics.Source)
+			continue
+		}
+
+		// metrics read from the same event should be listed together, otherwise series could be duplicated
+		stream := streamMetrics.Stream + "\x00" + streamMetrics.Source
+		if streams[stream] {
+			invalid(i, "%s of stream %s is listed more than once in stream metrics", streamMetrics.Source, streamMetrics.Stream)
+			continue
+		}
+		streams[stream] = true
+
+		if len(streamMetrics.Metrics) == 0 {
+			invalid(i, "stream %s metrics should specify at least one metric", streamMetrics.Stream)
+			continue
+		}
+
+		names := map[string]bool{}
+		for _, metric := range streamMetrics.Metrics {
+			if names[metric.Name] {
+				invalid(i, "metric %s is listed more than once for stream %s", metric.Name, streamMetrics.Stream)
+				continue
+			}
+			names[metric.Name] = true
+
+			if err := metric.validate(streamMetricLabels); err != nil {
+				invalid(i, "invalid metric of stream %s: %w", streamMetrics.Stream, err)
+				continue
+			}
+
+			if projectionMetricNames[metric.Name] {
+				invalid(i, "stream metric %s is already used as projection metric", metric.Name)
+				continue
+			}
+
+			// the same metric can be read from several streams, but its help and labels should not change
+			if other, exists := metricsByName[metric.Name]; exists && !other.sameAs(metric) {
+				invalid(i, "stream metric %s should have the same help and labels wherever it is used", metric.Name)
+				continue
+			}
+			metricsByName[metric.Name] = metric
+
+			stream := metric.Name + "\x00" + streamMetrics.Stream
+			if streamsByName[stream] {
+				invalid(i, "stream metric %s is read more than once from stream %s", metric.Name, streamMetrics.Stream)
+				continue
+			}
+			streamsByName[stream] = true
+		}
+	}
+
+	return errs
+}
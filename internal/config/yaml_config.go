@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/namsral/flag"
+	"gopkg.in/yaml.v3"
+)
+
+const yamlConfigFlagname = "config-file"
+
+// flags that only make sense on command line and can't be set in YAML config file
+var nonYAMLFlags = map[string]bool{
+	flag.DefaultConfigFlagname: true,
+	yamlConfigFlagname:         true,
+	"print-config":             true,
+}
+
+// yamlConfig holds values read from YAML (or JSON) config file. Keys of the file are flag names,
+// values are applied as flag defaults, so that flags, env variables and key=value config file take precedence.
+type yamlConfig struct {
+	path string
+	// lines of keys and of items of list values, used to report validation errors
+	lines     map[string]int
+	itemLines map[string][]int
+
+	values            map[string]string
+	streams           []string
+	projectionMetrics []ProjectionMetrics
+	streamMetrics     []StreamMetrics
+}
+
+func loadYAMLConfig(path string, fs *flag.FlagSet) (*yamlConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading config file: %w", err)
+	}
+
+	yamlConfig, err := parseYAMLConfig(content, fs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	yamlConfig.path = path
+
+	return yamlConfig, nil
+}
+
+func parseYAMLConfig(content []byte, fs *flag.FlagSet) (*yamlConfig, error) {
+	parsed := &yamlConfig{lines: map[string]int{}, itemLines: map[string][]int{}, values: map[string]string{}}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	if len(document.Content) == 0 {
+		return parsed, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, yamlError(root, "config should be a mapping of flag names to values")
+	}
+
+	var errs []error
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		if err := parsed.add(key, value, fs); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		parsed.lines[key.Value] = key.Line
+		if value.Kind == yaml.SequenceNode {
+			for _, item := range value.Content {
+				parsed.itemLines[key.Value] = append(parsed.itemLines[key.Value], item.Line)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return parsed, nil
+}
+
+func (parsed *yamlConfig) add(key *yaml.Node, value *yaml.Node, fs *flag.FlagSet) error {
+	name := key.Value
+
+	switch name {
+	case projectionMetricsKey:
+		if parsed.projectionMetrics != nil {
+			return yamlError(key, "duplicate key %q", name)
+		}
+
+		return decodeYAMLSection(key, value, &parsed.projectionMetrics)
+	case streamMetricsKey:
+		if parsed.streamMetrics != nil {
+			return yamlError(key, "duplicate key %q", name)
+		}
+
+		return decodeYAMLSection(key, value, &parsed.streamMetrics)
+	}
+
+	f := fs.Lookup(name)
+	if f == nil || nonYAMLFlags[name] {
+		return yamlError(key, "unknown key %q", name)
+	}
+
+	if _, exists := parsed.values[name]; exists || (name == "streams" && parsed.streams != nil) {
+		return yamlError(key, "duplicate key %q", name)
+	}
+
+	if name == "streams" && value.Kind == yaml.SequenceNode {
+		streams := make([]string, 0, len(value.Content))
+		for _, stream := range value.Content {
+			if stream.Kind != yaml.ScalarNode {
+				return yamlError(stream, "%q should be a list of stream names", name)
+			}
+			streams = append(streams, stream.Value)
+		}
+
+		parsed.streams = streams
+		return nil
+	}
+
+	if value.Kind != yaml.ScalarNode {
+		return yamlError(value, "%q should be a single value", name)
+	}
+
+	switch f.Value.(flag.Getter).Get().(type) {
+	case bool:
+		if _, err := strconv.ParseBool(value.Value); err != nil || value.Tag != "!!bool" {
+			return yamlError(value, "%q should be true or false, got %q", name, value.Value)
+		}
+	case uint:
+		if _, err := strconv.ParseUint(value.Value, 10, strconv.IntSize); err != nil || value.Tag != "!!int" {
+			return yamlError(value, "%q should be a non-negative integer, got %q", name, value.Value)
+		}
+	case time.Duration:
+		if _, err := time.ParseDuration(value.Value); err != nil {
+			return yamlError(value, "%q should be a duration like 5s or 1m30s, got %q", name, value.Value)
+		}
+	}
+
+	parsed.values[name] = value.Value
+	return nil
+}
+
+// decodeYAMLSection reads a list of nested items, like projection metrics, which have no flag equivalent
+func decodeYAMLSection[T any](key *yaml.Node, value *yaml.Node, section *[]T) error {
+	if value.Kind != yaml.SequenceNode {
+		return yamlError(value, "%q should be a list", key.Value)
+	}
+
+	// decoding a node directly ignores unknown fields, so the section is encoded again and decoded strictly
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return yamlError(value, "invalid %q: %v", key.Value, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	items := []T{}
+	if err := decoder.Decode(&items); err != nil {
+		return yamlError(value, "invalid %q: %v", key.Value, err)
+	}
+
+	*section = items
+	return nil
+}
+
+// apply sets YAML values as flag defaults, these are overridden by flags that are set explicitly
+func (parsed *yamlConfig) apply(fs *flag.FlagSet) error {
+	for name, value := range parsed.values {
+		if err := fs.Lookup(name).Value.Set(value); err != nil {
+			return fmt.Errorf("invalid value %q for %s: %w", value, name, err)
+		}
+	}
+
+	return nil
+}
+
+// errorWithPosition adds position in the config file to validation errors caused by settings read from the file,
+// settings whose values were overridden by flags or env variables are skipped
+func (parsed *yamlConfig) errorWithPosition(err error, overridden map[string]bool) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, parsed.errorWithPosition(err, overridden))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	var settingErr *settingError
+	if !errors.As(err, &settingErr) {
+		return err
+	}
+
+	for _, setting := range settingErr.settings {
+		line, exists := parsed.lines[setting]
+		if !exists || overridden[setting] {
+			continue
+		}
+
+		if itemLines := parsed.itemLines[setting]; settingErr.item >= 0 && settingErr.item < len(itemLines) {
+			line = itemLines[settingErr.item]
+		}
+
+		return fmt.Errorf("invalid config file %s: line %d: %w", parsed.path, line, err)
+	}
+
+	return err
+}
+
+func yamlError(node *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", node.Line, fmt.Sprintf(format, args...))
+}
+
+// findYAMLConfigFile finds path to YAML config file in arguments or environment before flags are parsed,
+// since values from the file are used as flag defaults
+func findYAMLConfigFile(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != yamlConfigFlagname {
+			continue
+		}
+
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return os.Getenv(strings.ToUpper(strings.ReplaceAll(yamlConfigFlagname, "-", "_")))
+}
@@ -0,0 +1,202 @@
+// Package jsonpath implements the subset of JSONPath needed to extract values from JSON documents
+// stored in EventStore, like projection state or event data. Supported are child keys (.key or ['key']),
+// array indexes ([0]) and wildcards (.* or [*]), wildcards match every key of an object or every element of an array.
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type segmentKind int
+
+const (
+	keySegment segmentKind = iota
+	indexSegment
+	wildcardSegment
+)
+
+type segment struct {
+	kind  segmentKind
+	key   string
+	index int
+}
+
+// Path is a compiled path expression
+type Path struct {
+	expression string
+	segments   []segment
+}
+
+// Match is a value found by a path, together with the keys (or array indexes) matched by each wildcard
+type Match struct {
+	Wildcards []string
+	Value     any
+}
+
+// Compile parses path expressions like $.regions.*.count, leading $ is optional
+func Compile(expression string) (*Path, error) {
+	path := &Path{expression: expression}
+
+	rest := strings.TrimPrefix(expression, "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	for rest != "" {
+		var seg segment
+		var err error
+
+		switch rest[0] {
+		case '.':
+			seg, rest, err = parseDotSegment(rest[1:])
+		case '[':
+			seg, rest, err = parseBracketSegment(rest[1:])
+		default:
+			err = fmt.Errorf("unexpected character %q", rest[0])
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", expression, err)
+		}
+
+		path.segments = append(path.segments, seg)
+	}
+
+	if len(path.segments) == 0 {
+		return nil, fmt.Errorf("invalid path %q: path should select a value inside of the document", expression)
+	}
+
+	return path, nil
+}
+
+func parseDotSegment(rest string) (segment, string, error) {
+	end := strings.IndexAny(rest, ".[")
+	if end < 0 {
+		end = len(rest)
+	}
+
+	key := rest[:end]
+	if key == "" {
+		return segment{}, "", fmt.Errorf("empty key")
+	}
+	if strings.ContainsAny(key, "]'\"") {
+		return segment{}, "", fmt.Errorf("key %q should be quoted in brackets", key)
+	}
+	if key == "*" {
+		return segment{kind: wildcardSegment}, rest[end:], nil
+	}
+
+	return segment{kind: keySegment, key: key}, rest[end:], nil
+}
+
+func parseBracketSegment(rest string) (segment, string, error) {
+	if rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+		quote := rest[0]
+		end := strings.IndexByte(rest[1:], quote) + 1
+		if end == 0 || len(rest) <= end+1 || rest[end+1] != ']' {
+			return segment{}, "", fmt.Errorf("unterminated quoted key")
+		}
+
+		return segment{kind: keySegment, key: rest[1:end]}, rest[end+2:], nil
+	}
+
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return segment{}, "", fmt.Errorf("missing ]")
+	}
+
+	inside := rest[:end]
+	if inside == "*" {
+		return segment{kind: wildcardSegment}, rest[end+1:], nil
+	}
+
+	index, err := strconv.Atoi(inside)
+	if err != nil || index < 0 {
+		return segment{}, "", fmt.Errorf("array index should be a non-negative integer, got %q", inside)
+	}
+
+	return segment{kind: indexSegment, index: index}, rest[end+1:], nil
+}
+
+// String returns the expression the path was compiled from
+func (path *Path) String() string {
+	return path.expression
+}
+
+// Wildcards returns number of wildcards in the path, which is the number of keys reported with each match
+func (path *Path) Wildcards() int {
+	count := 0
+	for _, seg := range path.segments {
+		if seg.kind == wildcardSegment {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Find returns values selected by the path in a document decoded with encoding/json, object keys
+// matched by wildcards are visited in sorted order so that results are stable
+func (path *Path) Find(document any) []Match {
+	var matches []Match
+	path.find(document, 0, nil, &matches)
+
+	return matches
+}
+
+func (path *Path) find(value any, depth int, wildcards []string, matches *[]Match) {
+	if depth == len(path.segments) {
+		*matches = append(*matches, Match{Wildcards: append([]string(nil), wildcards...), Value: value})
+		return
+	}
+
+	seg := path.segments[depth]
+	switch node := value.(type) {
+	case map[string]any:
+		switch seg.kind {
+		case keySegment:
+			if child, ok := node[seg.key]; ok {
+				path.find(child, depth+1, wildcards, matches)
+			}
+		case wildcardSegment:
+			keys := make([]string, 0, len(node))
+			for key := range node {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				path.find(node[key], depth+1, append(wildcards, key), matches)
+			}
+		}
+	case []any:
+		switch seg.kind {
+		case indexSegment:
+			if seg.index < len(node) {
+				path.find(node[seg.index], depth+1, wildcards, matches)
+			}
+		case wildcardSegment:
+			for i, child := range node {
+				path.find(child, depth+1, append(wildcards, strconv.Itoa(i)), matches)
+			}
+		}
+	}
+}
+
+// Number converts matched value to float64, numbers are used as is and booleans are reported as 0 or 1
+func Number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
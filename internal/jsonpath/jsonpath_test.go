@@ -0,0 +1,95 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const document = `{
+	"total": 12,
+	"active": true,
+	"name": "orders",
+	"regions": {
+		"us": {"count": 5, "tags": ["a"]},
+		"eu": {"count": 7}
+	},
+	"shards": [{"lag": 1}, {"lag": 3}],
+	"odd.key": 4
+}`
+
+func Test_Find(t *testing.T) {
+	var decoded any
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expression string
+		expected   []Match
+	}{
+		{"$.total", []Match{{Value: 12.0}}},
+		{"total", []Match{{Value: 12.0}}},
+		{"$.regions.us.count", []Match{{Value: 5.0}}},
+		{"$.regions.*.count", []Match{{Wildcards: []string{"eu"}, Value: 7.0}, {Wildcards: []string{"us"}, Value: 5.0}}},
+		{"$['regions'][*]['count']", []Match{{Wildcards: []string{"eu"}, Value: 7.0}, {Wildcards: []string{"us"}, Value: 5.0}}},
+		{"$.shards[1].lag", []Match{{Value: 3.0}}},
+		{"$.shards[*].lag", []Match{{Wildcards: []string{"0"}, Value: 1.0}, {Wildcards: []string{"1"}, Value: 3.0}}},
+		{`$["odd.key"]`, []Match{{Value: 4.0}}},
+		{"$.missing", nil},
+		{"$.shards[5].lag", nil},
+		{"$.total.nested", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			path, err := Compile(test.expression)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if diff := cmp.Diff(path.Find(decoded), test.expected); diff != "" {
+				t.Errorf("wrong matches, diff: %v", diff)
+			}
+		})
+	}
+}
+
+func Test_Compile_Invalid_Paths(t *testing.T) {
+	for _, expression := range []string{"", "$", "$.", "$..total", "$.a[", "$.a[x]", "$.a[-1]", "$['a'", "$.a]"} {
+		if _, err := Compile(expression); err == nil {
+			t.Errorf("expected error for %q", expression)
+		}
+	}
+}
+
+func Test_Wildcards(t *testing.T) {
+	path, err := Compile("$.regions.*.shards[*].lag")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wildcards := path.Wildcards(); wildcards != 2 {
+		t.Errorf("expected 2 wildcards, got %d", wildcards)
+	}
+}
+
+func Test_Number(t *testing.T) {
+	for _, test := range []struct {
+		value    any
+		expected float64
+		ok       bool
+	}{
+		{1.5, 1.5, true},
+		{true, 1, true},
+		{false, 0, true},
+		{"1", 0, false},
+		{nil, 0, false},
+		{map[string]any{}, 0, false},
+	} {
+		if number, ok := Number(test.value); number != test.expected || ok != test.ok {
+			t.Errorf("expected %v, %v for %v, got %v, %v", test.expected, test.ok, test.value, number, ok)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConfigUpdater is implemented by components that can switch to new config without restart
+type ConfigUpdater interface {
+	UpdateConfig(newConfig *config.Config)
+}
+
+// ConfigUpdaterFunc adapts ordinary function to ConfigUpdater
+type ConfigUpdaterFunc func(newConfig *config.Config)
+
+func (f ConfigUpdaterFunc) UpdateConfig(newConfig *config.Config) {
+	f(newConfig)
+}
+
+type configReloader struct {
+	mutex    sync.Mutex
+	port     uint
+	load     func() (*config.Config, error)
+	updaters []ConfigUpdater
+
+	lastReloadSuccessful prometheus.Gauge
+}
+
+// EnableReload makes the server reload config on SIGHUP or POST to /-/reload. New config is loaded
+// and validated first, and only then passed to updaters.
+func (server *ExporterServer) EnableReload(load func() (*config.Config, error), updaters ...ConfigUpdater) {
+	reloader := &configReloader{
+		port:     server.config().Port,
+		load:     load,
+		updaters: append([]ConfigUpdater{server}, updaters...),
+		lastReloadSuccessful: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "eventstore_exporter_config_last_reload_successful",
+			Help: "Whether the last configuration reload attempt was successful",
+		}),
+	}
+	reloader.lastReloadSuccessful.Set(1)
+
+	server.reloader = reloader
+	server.registry.MustRegister(reloader.lastReloadSuccessful)
+	server.mux.HandleFunc("/-/reload", reloader.handleReloadRequest)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			log.Info("Received SIGHUP, reloading config")
+			reloader.reload() // nolint: errcheck
+		}
+	}()
+}
+
+func (reloader *configReloader) handleReloadRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Only POST requests allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := reloader.reload(); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reload config: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (reloader *configReloader) reload() error {
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+
+	newConfig, err := reloader.load()
+	if err != nil {
+		log.WithError(err).Error("Error when reloading config, keeping previous config")
+		reloader.lastReloadSuccessful.Set(0)
+
+		return err
+	}
+
+	if newConfig.Port != reloader.port {
+		log.WithField("port", reloader.port).Warn("Port change requires restart, keeping previous port")
+	}
+
+	for _, updater := range reloader.updaters {
+		updater.UpdateConfig(newConfig)
+	}
+
+	reloader.lastReloadSuccessful.Set(1)
+	log.Info("Config reloaded")
+
+	return nil
+}
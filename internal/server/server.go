@@ -1,8 +1,11 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/marcinbudny/eventstore_exporter/internal/collector"
@@ -12,26 +15,40 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+
 type ExporterServer struct {
-	config    *config.Config
-	collector *collector.Collector
-	mux       *http.ServeMux
+	currentConfig atomic.Pointer[config.Config]
+	collector     *collector.Collector
+	mux           *http.ServeMux
+	registry      *prometheus.Registry
+	reloader      *configReloader
 }
 
 func NewExporterServer(config *config.Config, collector *collector.Collector) *ExporterServer {
 	server := &ExporterServer{
-		config:    config,
 		collector: collector,
 		mux:       http.NewServeMux(),
+		registry:  prometheus.NewRegistry(),
 	}
+	server.currentConfig.Store(config)
 	server.serveLandingPage()
 	server.serveMetrics()
 
 	return server
 }
 
+// UpdateConfig swaps config used by the server, port can't be changed without restart
+func (server *ExporterServer) UpdateConfig(newConfig *config.Config) {
+	server.currentConfig.Store(newConfig)
+}
+
+func (server *ExporterServer) config() *config.Config {
+	return server.currentConfig.Load()
+}
+
 func (server *ExporterServer) ListenAndServe() {
-	listenAddr := fmt.Sprintf(":%d", server.config.Port)
+	listenAddr := fmt.Sprintf(":%d", server.config().Port)
 
 	srv := &http.Server{
 		Addr:         listenAddr,
@@ -60,8 +77,46 @@ func (server *ExporterServer) serveLandingPage() {
 }
 
 func (server *ExporterServer) serveMetrics() {
-	registry := prometheus.NewRegistry()
-	registry.MustRegister(server.collector)
+	server.mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := server.scrapeContext(r)
+		defer cancel()
+
+		// collector is registered per request, so that it gets stats within the request context
+		scrapeRegistry := prometheus.NewRegistry()
+		scrapeRegistry.MustRegister(server.collector.WithContext(ctx))
+
+		gatherers := prometheus.Gatherers{server.registry, scrapeRegistry}
+		promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
+
+// scrapeContext returns request context with deadline shortly before Prometheus gives up on the scrape,
+// so that slow EventStore calls time out and get reported before that
+func (server *ExporterServer) scrapeContext(r *http.Request) (context.Context, context.CancelFunc) {
+	timeout, ok := scrapeTimeout(r.Header.Get(scrapeTimeoutHeader), server.config().ScrapeTimeoutOffset)
+	if !ok {
+		return context.WithCancel(r.Context())
+	}
+
+	return context.WithTimeout(r.Context(), timeout)
+}
+
+func scrapeTimeout(header string, offset time.Duration) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseFloat(header, 64)
+	if err != nil || seconds <= 0 {
+		log.WithField("value", header).Warn("Invalid scrape timeout header")
+		return 0, false
+	}
+
+	timeout := time.Duration(seconds * float64(time.Second))
+	if timeout <= offset {
+		// offset would leave no time for the scrape, use the full scrape timeout instead
+		return timeout, true
+	}
 
-	server.mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return timeout - offset, true
 }
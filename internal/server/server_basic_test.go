@@ -4,6 +4,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
 )
 
 func Test_LandingPage(t *testing.T) {
@@ -35,13 +37,74 @@ func Test_BasicMetrics(t *testing.T) {
 	assertHasMetric(t, metrics, "eventstore_sys_total_memory_bytes", "gauge")
 	assertHasMetric(t, metrics, "eventstore_process_cpu", "gauge")
 	assertHasMetric(t, metrics, "eventstore_process_memory_bytes", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_start_time_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_uptime_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_threads", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_contentions_per_second", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_thrown_exceptions_per_second", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_gc_allocation_speed_bytes_per_second", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_gc_fragmentation_ratio", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_gc_collections_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_process_gc_generation_size_bytes", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_gc_time_ratio", "gauge")
+	assertHasMetric(t, metrics, "eventstore_process_gc_heap_size_bytes", "gauge")
 	assertHasMetric(t, metrics, "eventstore_queue_items_processed_total", "counter")
 	assertHasMetric(t, metrics, "eventstore_queue_length", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_length_current_try_peak", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_length_lifetime_peak", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_avg_items_per_second", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_avg_processing_time_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_idle_time_ratio", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_current_idle_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_current_message_processing_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_queue_info", "gauge")
+	assertHasMetric(t, metrics, "eventstore_writer_last_flush_size_bytes", "gauge")
+	assertHasMetric(t, metrics, "eventstore_writer_mean_flush_size_bytes", "gauge")
+	assertHasMetric(t, metrics, "eventstore_writer_max_flush_size_bytes", "gauge")
+	assertHasMetric(t, metrics, "eventstore_writer_last_flush_delay_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_writer_mean_flush_delay_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_writer_max_flush_delay_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_writer_queued_flush_messages", "gauge")
+	assertHasMetric(t, metrics, "eventstore_read_index_cache_hits_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_read_index_cache_misses_total", "counter")
 	assertHasMetric(t, metrics, "eventstore_tcp_connections", "gauge")
 	assertHasMetric(t, metrics, "eventstore_tcp_received_bytes", "gauge")
 	assertHasMetric(t, metrics, "eventstore_tcp_sent_bytes", "gauge")
 }
 
+func Test_BasicMetrics_Version2(t *testing.T) {
+	es := prepareExporterServerWithConfig(func(config *config.Config) {
+		config.MetricsVersion = 2
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertHasMetric(t, metrics, "eventstore_disk_io_read_bytes_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_disk_io_read_ops_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_disk_io_write_ops_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_disk_io_written_bytes_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_tcp_received_bytes_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_tcp_sent_bytes_total", "counter")
+	assertHasNoMetric(t, metrics, "eventstore_disk_io_read_bytes")
+	assertHasNoMetric(t, metrics, "eventstore_tcp_sent_bytes")
+}
+
+func Test_BasicMetrics_Version2_WithLegacyGauges(t *testing.T) {
+	es := prepareExporterServerWithConfig(func(config *config.Config) {
+		config.MetricsVersion = 2
+		config.EnableLegacyGaugeMetrics = true
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertHasMetric(t, metrics, "eventstore_disk_io_read_bytes_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_disk_io_read_bytes", "gauge")
+	assertHasMetric(t, metrics, "eventstore_tcp_sent_bytes_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_tcp_sent_bytes", "gauge")
+}
+
 func Test_EventStoreUp_Up(t *testing.T) {
 	es := prepareExporterServer()
 	ts := httptest.NewServer(es.mux)
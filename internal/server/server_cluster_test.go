@@ -16,4 +16,7 @@ func Test_ClusterMetrics(t *testing.T) {
 	assertHasMetric(t, metrics, "eventstore_cluster_member_is_follower", "gauge")
 	assertHasMetric(t, metrics, "eventstore_cluster_member_is_leader", "gauge")
 	assertHasMetric(t, metrics, "eventstore_cluster_member_is_readonly_replica", "gauge")
+	assertHasMetric(t, metrics, "eventstore_cluster_leader_changes_total", "counter")
+	assertHasMetric(t, metrics, "eventstore_cluster_epoch_number", "gauge")
+	assertHasMetric(t, metrics, "eventstore_cluster_member_clock_skew_seconds", "gauge")
 }
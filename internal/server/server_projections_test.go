@@ -1,8 +1,14 @@
 package server
 
 import (
+	"context"
+	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
 )
 
 func Test_ProjectionMetrics(t *testing.T) {
@@ -20,6 +26,79 @@ func Test_ProjectionMetrics(t *testing.T) {
 	assertHasMetric(t, metrics, "eventstore_projection_status", "gauge")
 	assertHasMetric(t, metrics, "eventstore_projection_progress", "gauge")
 	assertHasMetric(t, metrics, "eventstore_projection_events_processed_after_restart_total", "counter")
+	assertMetric(t, metrics, "eventstore_projection_info", "gauge",
+		metricByLabelValue("projection", "$by_category"), hasValue(1))
+	assertMetric(t, metrics, "eventstore_projections_enabled", "gauge", singleValuedMetric, hasValue(1))
+}
+
+func Test_Grpc_ProjectionMetrics(t *testing.T) {
+	if !shouldRunProjectionsTest(t) {
+		t.Log("Skipping projection metrics")
+		return
+	}
+
+	es := prepareExporterServerWithConfig(func(config *config.Config) {
+		config.ProjectionStatsSource = "grpc"
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertMetric(t, metrics, "eventstore_projection_running", "gauge",
+		metricByLabelValue("projection", "$by_category"), anyValue)
+	assertHasMetric(t, metrics, "eventstore_projection_progress", "gauge")
+	assertMetric(t, metrics, "eventstore_projections_enabled", "gauge", singleValuedMetric, hasValue(1))
+}
+
+func Test_UserDefinedProjectionMetrics(t *testing.T) {
+	if !shouldRunProjectionsTest(t) {
+		t.Log("Skipping projection metrics")
+		return
+	}
+
+	esClient := getEsClient(t)
+	defer esClient.Close()
+
+	streamID, projectionName := newUUID(), "test-"+newUUID()
+	writeTestEvents(t, 3, streamID, esClient)
+	createProjection(t, esClient, projectionName, fmt.Sprintf(`fromStream('%s').when({
+		$init: function () { return { total: 0, types: {} }; },
+		$any: function (state, event) {
+			state.total++;
+			state.types[event.eventType] = (state.types[event.eventType] || 0) + 1;
+			return state;
+		}
+	})`, streamID))
+
+	time.Sleep(time.Millisecond * 2000)
+
+	es := prepareExporterServerWithConfig(func(cfg *config.Config) {
+		cfg.ProjectionMetrics = []config.ProjectionMetrics{{
+			Projection: projectionName,
+			Source:     config.ProjectionMetricsSourceState,
+			Metrics: []config.JSONMetric{
+				{Name: "test_events_total", Path: "$.total"},
+				{Name: "test_events_by_type", Path: "$.types.*", Labels: []string{"type"}},
+			},
+		}}
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertMetric(t, metrics, "test_events_total", "gauge",
+		metricByLabelValue("projection", projectionName), hasValue(3))
+	assertMetric(t, metrics, "test_events_by_type", "gauge",
+		metricByLabelValue("type", "TestEvent"), hasValue(3))
+}
+
+func createProjection(t *testing.T, esClient *esdb.Client, name string, query string) {
+	t.Helper()
+
+	projectionClient := esdb.NewProjectionClientFromExistingClient(esClient)
+	if err := projectionClient.Create(context.Background(), name, query, esdb.CreateProjectionOptions{}); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func shouldRunProjectionsTest(t *testing.T) bool {
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_ConfigReload(t *testing.T) {
+	es := prepareExporterServer()
+
+	newConfig := &config.Config{EnableScavengeStats: true}
+	var updatedConfig *config.Config
+	es.EnableReload(
+		func() (*config.Config, error) { return newConfig, nil },
+		ConfigUpdaterFunc(func(c *config.Config) { updatedConfig = c }),
+	)
+
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	response, err := http.Post(ts.URL+"/-/reload", "", nil) // nolint: noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200, got %d", response.StatusCode)
+	}
+	if updatedConfig != newConfig {
+		t.Error("Expected new config to be passed to updater")
+	}
+	if value := testutil.ToFloat64(es.reloader.lastReloadSuccessful); value != 1 {
+		t.Errorf("Expected last reload to be successful, got %v", value)
+	}
+}
+
+func Test_ConfigReload_InvalidConfig(t *testing.T) {
+	es := prepareExporterServer()
+
+	updated := false
+	es.EnableReload(
+		func() (*config.Config, error) { return nil, errors.New("invalid config") },
+		ConfigUpdaterFunc(func(*config.Config) { updated = true }),
+	)
+
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	response, err := http.Post(ts.URL+"/-/reload", "", nil) // nolint: noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected status code 500, got %d", response.StatusCode)
+	}
+	if updated {
+		t.Error("Expected invalid config not to be passed to updater")
+	}
+	if value := testutil.ToFloat64(es.reloader.lastReloadSuccessful); value != 0 {
+		t.Errorf("Expected last reload to be unsuccessful, got %v", value)
+	}
+}
+
+func Test_ConfigReload_OnlyPost(t *testing.T) {
+	es := prepareExporterServer()
+	es.EnableReload(func() (*config.Config, error) { return &config.Config{}, nil })
+
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	response, err := http.Get(ts.URL + "/-/reload") // nolint: noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status code 405, got %d", response.StatusCode)
+	}
+}
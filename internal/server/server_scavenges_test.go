@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_ScavengeMetrics(t *testing.T) {
+	startScavenge(t)
+	time.Sleep(time.Millisecond * 2000)
+
+	es := prepareExporterServerWithConfig(func(config *config.Config) {
+		config.EnableScavengeStats = true
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertHasMetric(t, metrics, "eventstore_scavenge_running", "gauge")
+	assertHasMetric(t, metrics, "eventstore_scavenge_last_start_timestamp_seconds", "gauge")
+	assertHasMetric(t, metrics, "eventstore_scavenge_last_duration_seconds", "gauge")
+}
+
+func Test_NoScavengeMetrics_When_Disabled(t *testing.T) {
+	es := prepareExporterServer()
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertHasNoMetric(t, metrics, "eventstore_scavenge_running")
+}
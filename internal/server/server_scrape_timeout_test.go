@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_ScrapeTimeout(t *testing.T) {
+	tests := []struct {
+		header          string
+		offset          time.Duration
+		expectedTimeout time.Duration
+		expectedOk      bool
+	}{
+		{header: "", offset: 500 * time.Millisecond, expectedOk: false},
+		{header: "invalid", offset: 500 * time.Millisecond, expectedOk: false},
+		{header: "-1", offset: 500 * time.Millisecond, expectedOk: false},
+		{header: "10", offset: 500 * time.Millisecond, expectedTimeout: 9500 * time.Millisecond, expectedOk: true},
+		{header: "2.5", offset: 0, expectedTimeout: 2500 * time.Millisecond, expectedOk: true},
+		{header: "0.3", offset: 500 * time.Millisecond, expectedTimeout: 300 * time.Millisecond, expectedOk: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			timeout, ok := scrapeTimeout(test.header, test.offset)
+			if ok != test.expectedOk || timeout != test.expectedTimeout {
+				t.Errorf("Expected %v, %v, got %v, %v", test.expectedTimeout, test.expectedOk, timeout, ok)
+			}
+		})
+	}
+}
+
+func Test_ScrapeContextDeadlineFromHeader(t *testing.T) {
+	es := prepareExporterServerWithConfig(func(config *config.Config) {
+		config.ScrapeTimeoutOffset = time.Second
+	})
+
+	request := httptest.NewRequest("GET", "/metrics", nil)
+	request.Header.Set(scrapeTimeoutHeader, "5")
+
+	ctx, cancel := es.scrapeContext(request)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("Expected context to have deadline")
+	}
+
+	if remaining := time.Until(deadline); remaining > 4*time.Second || remaining < 3*time.Second {
+		t.Errorf("Expected deadline in about 4s, got %v", remaining)
+	}
+}
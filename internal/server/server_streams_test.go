@@ -1,9 +1,11 @@
 package server
 
 import (
+	"context"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
 	"github.com/marcinbudny/eventstore_exporter/internal/config"
 )
 
@@ -28,3 +30,38 @@ func Test_StreamStats(t *testing.T) {
 	assertMetric(t, metrics, "eventstore_stream_last_event_number", "gauge", metricByLabelValue("event_stream_id", stream1ID), hasValue(float64(12-1))) // event ids start at 0
 	assertMetric(t, metrics, "eventstore_stream_last_event_number", "gauge", metricByLabelValue("event_stream_id", stream2ID), hasValue(float64(9-1)))  // event ids start at 0
 }
+
+func Test_StreamMetrics(t *testing.T) {
+	client := getEsClient(t)
+	streamID := newUUID()
+
+	heartbeats := []esdb.EventData{
+		{EventType: "Heartbeat", ContentType: esdb.ContentTypeJson, Data: []byte(`{"service": "billing", "position": 10}`)},
+		{EventType: "Heartbeat", ContentType: esdb.ContentTypeJson, Data: []byte(`{"service": "billing", "position": 15}`), Metadata: []byte(`{"lag": 2}`)},
+	}
+	if _, err := client.AppendToStream(context.Background(), streamID, esdb.AppendToStreamOptions{ExpectedRevision: esdb.Any{}}, heartbeats...); err != nil {
+		t.Fatal(err)
+	}
+
+	es := prepareExporterServerWithConfig(func(cfg *config.Config) {
+		cfg.StreamMetrics = []config.StreamMetrics{
+			{
+				Stream:  streamID,
+				Source:  config.StreamMetricsSourceData,
+				Metrics: []config.JSONMetric{{Name: "test_service_position", Path: "$.position", LabelFields: map[string]string{"service": "$.service"}}},
+			},
+			{
+				Stream:  streamID,
+				Source:  config.StreamMetricsSourceMetadata,
+				Metrics: []config.JSONMetric{{Name: "test_service_lag", Path: "$.lag"}},
+			},
+		}
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+
+	assertMetric(t, metrics, "test_service_position", "gauge", metricByLabelValue("service", "billing"), hasValue(15))
+	assertMetric(t, metrics, "test_service_lag", "gauge", metricByLabelValue("stream", streamID), hasValue(2))
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/EventStore/EventStore-Client-Go/v4/esdb"
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
 )
 
 func Test_Basic_SubscriptionMetrics(t *testing.T) {
@@ -39,6 +40,34 @@ func Test_Basic_SubscriptionMetrics(t *testing.T) {
 
 }
 
+func Test_Grpc_SubscriptionMetrics(t *testing.T) {
+	if !shouldRunSubscriptionTests() {
+		t.Log("Skipping subscriptions tests")
+		return
+	}
+
+	totalCount := 60
+	ackCount := 10
+	parkCount := 20
+	_, groupName := prepareSubscriptionEnvironment(t, totalCount, ackCount, parkCount)
+
+	es := prepareExporterServerWithConfig(func(config *config.Config) {
+		config.SubscriptionStatsSource = "grpc"
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertMetric(t, metrics, "eventstore_subscription_connections", "gauge",
+		metricByLabelValue("group_name", groupName), anyValue)
+	assertMetric(t, metrics, "eventstore_subscription_last_known_event_number", "gauge",
+		metricByLabelValue("group_name", groupName), nonZeroValue)
+	assertMetric(t, metrics, "eventstore_subscription_items_processed_total", "counter",
+		metricByLabelValue("group_name", groupName), nonZeroValue)
+	assertMetric(t, metrics, "eventstore_subscription_parked_messages", "gauge",
+		metricByLabelValue("group_name", groupName), hasValue(float64(parkCount)))
+}
+
 func Test_ParkedMessages_SubscriptionMetric(t *testing.T) {
 	if !shouldRunSubscriptionTests() {
 		t.Log("Skipping subscriptions tests")
@@ -59,6 +88,23 @@ func Test_ParkedMessages_SubscriptionMetric(t *testing.T) {
 		metricByLabelValue("group_name", groupName), hasValue(float64(parkCount)))
 }
 
+func Test_ParkedMessagesSource_SubscriptionMetric(t *testing.T) {
+	if !shouldRunSubscriptionTests() {
+		t.Log("Skipping subscriptions tests")
+		return
+	}
+
+	_, groupName := prepareSubscriptionEnvironment(t, 10, 5, 5)
+
+	es := prepareExporterServer()
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertMetric(t, metrics, "eventstore_subscription_parked_messages_source", "gauge",
+		metricByLabelValue("group_name", groupName), hasValue(1))
+}
+
 func Test_OldestParkedMessage_SubscriptionMetric(t *testing.T) {
 	if !shouldRunSubscriptionTests() {
 		t.Log("Skipping subscriptions tests")
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/marcinbudny/eventstore_exporter/internal/config"
+)
+
+func Test_TLSCertificateMetrics(t *testing.T) {
+	if os.Getenv("TEST_CLUSTER_MODE") != "cluster" {
+		t.Skip("TLS certificate metrics are only available when EventStore runs over TLS")
+	}
+
+	es := prepareExporterServerWithConfig(func(config *config.Config) {
+		config.EnableTLSCertificateStats = true
+	})
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertHasMetric(t, metrics, "eventstore_tls_certificate_not_after_seconds", "gauge")
+}
+
+func Test_NoTLSCertificateMetrics_When_Disabled(t *testing.T) {
+	es := prepareExporterServer()
+	ts := httptest.NewServer(es.mux)
+	defer ts.Close()
+
+	metrics := getMetrics(ts.URL, t)
+	assertHasNoMetric(t, metrics, "eventstore_tls_certificate_not_after_seconds")
+}
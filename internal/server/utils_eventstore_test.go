@@ -57,6 +57,26 @@ func replayParkedMessages(t *testing.T, streamID string, groupName string) {
 	}
 }
 
+func startScavenge(t *testing.T) {
+	t.Helper()
+
+	httpClient := getEventstoreHTTPClient()
+
+	req, _ := http.NewRequest(http.MethodPost, getEventStoreURL()+"/admin/scavenge", nil)
+	req.SetBasicAuth("admin", "changeit")
+	req.Header.Add("Accept", "application/json")
+	res, errPost := httpClient.Do(req)
+
+	if errPost != nil {
+		t.Fatal(errPost)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Unable to start scavenge, status code %d", res.StatusCode)
+	}
+}
+
 func getEsInfo(t *testing.T) *client.EsInfo {
 	t.Helper()
 
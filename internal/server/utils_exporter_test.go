@@ -21,6 +21,8 @@ func prepareExporterServerWithConfig(updateConfig func(*config.Config)) *Exporte
 		EventStorePassword:        "changeit",
 		InsecureSkipVerify:        true,
 		Timeout:                   time.Second * 10,
+		MaxConcurrentReads:        20,
+		ReadTimeout:               time.Second * 3,
 		EnableParkedMessagesStats: true,
 		EnableTCPConnectionStats:  true,
 	}
@@ -43,6 +45,8 @@ func prepareExporterServerWithInvalidConnection() *ExporterServer {
 		EventStorePassword: "changeit",
 		InsecureSkipVerify: true,
 		Timeout:            time.Second * 10,
+		MaxConcurrentReads: 20,
+		ReadTimeout:        time.Second * 3,
 	}
 
 	client := client.New(config)